@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Agent bundles a system prompt with a restricted subset of the tools
+// registered on defaultTools, an optional model override, and a set of
+// files/URLs whose content is folded into the first user message for
+// lightweight RAG.
+type Agent struct {
+	Name         string            `yaml:"name" json:"name"`
+	SystemPrompt string            `yaml:"system_prompt" json:"system_prompt"`
+	Model        string            `yaml:"model,omitempty" json:"model,omitempty"`
+	Tools        []string          `yaml:"tools,omitempty" json:"tools,omitempty"`
+	Attachments  []string          `yaml:"attachments,omitempty" json:"attachments,omitempty"`
+	ToolPolicies map[string]string `yaml:"tool_policies,omitempty" json:"tool_policies,omitempty"`
+	MCPServers   []MCPServerConfig `yaml:"mcp_servers,omitempty" json:"mcp_servers,omitempty"`
+}
+
+// MCPServerConfig describes one MCP server to connect to on startup, over
+// either a stdio (Command/Args) or streamable-HTTP (URL/Headers) transport.
+// Exactly one of the two forms is expected to be set.
+type MCPServerConfig struct {
+	Name    string            `yaml:"name" json:"name"`
+	Command string            `yaml:"command,omitempty" json:"command,omitempty"`
+	Args    []string          `yaml:"args,omitempty" json:"args,omitempty"`
+	URL     string            `yaml:"url,omitempty" json:"url,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// DefaultAgentConfigPath returns ~/.config/pinky/agents.yaml (or the
+// platform equivalent), the default location Start() loads agents from.
+func DefaultAgentConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pinky", "agents.yaml")
+}
+
+// LoadAgents parses the agent config file at path, keyed by Agent.Name.
+// Both YAML and JSON are accepted; the format is chosen by file extension.
+func LoadAgents(path string) (map[string]Agent, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var agents []Agent
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.Unmarshal(b, &agents)
+	} else {
+		err = yaml.Unmarshal(b, &agents)
+	}
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]Agent, len(agents))
+	for _, a := range agents {
+		byName[a.Name] = a
+	}
+	return byName, nil
+}
+
+// SaveAgents writes agents back to path in the format LoadAgents would read
+// it as (YAML unless the extension is .json), overwriting any existing file.
+func SaveAgents(path string, agents map[string]Agent) error {
+	list := make([]Agent, 0, len(agents))
+	for _, a := range agents {
+		list = append(list, a)
+	}
+	var b []byte
+	var err error
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		b, err = json.MarshalIndent(list, "", "  ")
+	} else {
+		b, err = yaml.Marshal(list)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// toolNamesFor returns the set of tool names agent restricts a Brain to, or
+// nil (meaning every tool registered on the manager) for a nil agent. A set
+// rather than a resolved map[string]tool.Tool snapshot, so lookups always go through the
+// manager's own locked accessors instead of a second, unsynchronized copy.
+func toolNamesFor(agent *Agent) map[string]bool {
+	if agent == nil {
+		return nil
+	}
+	names := make(map[string]bool, len(agent.Tools))
+	for _, name := range agent.Tools {
+		names[name] = true
+	}
+	return names
+}
+
+// attachmentContent reads a file or fetches a URL, returning its raw
+// content.
+func attachmentContent(ctx context.Context, ref string) (string, error) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref, nil)
+		if err != nil {
+			return "", err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		b, err := io.ReadAll(res.Body)
+		return string(b), err
+	}
+	b, err := os.ReadFile(ref)
+	return string(b), err
+}
+
+// renderAttachments loads every attachment declared on agent and joins them
+// into a block suitable for prepending to the initial user message.
+func renderAttachments(ctx context.Context, agent *Agent) (string, error) {
+	if agent == nil || len(agent.Attachments) == 0 {
+		return "", nil
+	}
+	var out strings.Builder
+	for _, ref := range agent.Attachments {
+		content, err := attachmentContent(ctx, ref)
+		if err != nil {
+			return "", fmt.Errorf("loading attachment %q: %w", ref, err)
+		}
+		fmt.Fprintf(&out, "<attachment src=%q>\n%s\n</attachment>\n\n", ref, content)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/charmbracelet/huh"
+)
+
+// editInEditor writes content to a temp file, opens it in $EDITOR (falling
+// back to vi), and returns the edited content.
+func editInEditor(content string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	f, err := os.CreateTemp("", "pinky-edit-*.md")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	cmd := exec.Command(editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	edited, err := os.ReadFile(path)
+	return string(edited), err
+}
+
+// selectMessageIndex lets the user pick a position in history to act on.
+func (b *Brain) selectMessageIndex(title string) (int, error) {
+	options := make([]huh.Option[int], 0, len(b.history))
+	for i, m := range b.history {
+		options = append(options, huh.NewOption(fmt.Sprintf("%d: %s — %.60s", i, m.Role, printMessage(m)), i))
+	}
+	var idx int
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[int]().Title(title).Options(options...).Value(&idx),
+	)).Run()
+	return idx, err
+}
+
+// editAndFork lets the user edit any prior message in $EDITOR, then forks a
+// new sibling branch from that point: the original branch is preserved on
+// disk and the conversation continues from the edited message.
+func (b *Brain) editAndFork(ctx context.Context) error {
+	idx, err := b.selectMessageIndex("edit which message?")
+	if err != nil {
+		return err
+	}
+	content, err := editInEditor(b.history[idx].Content)
+	if err != nil {
+		return err
+	}
+	node, err := b.store.Message(b.path[idx])
+	if err != nil {
+		return err
+	}
+	edited := node.Message
+	edited.Content = content
+	if _, err := b.store.AppendMessage(b.conversationID, node.ParentID, edited); err != nil {
+		return err
+	}
+	return b.hydrate()
+}
+
+// switchBranch lets the user pick a different sibling at a branch point,
+// moving the conversation head to the deepest point previously explored
+// down that sibling (see Store.Leaf) without editing anything.
+func (b *Brain) switchBranch(ctx context.Context) error {
+	idx, err := b.selectMessageIndex("switch branch at which message?")
+	if err != nil {
+		return err
+	}
+	siblings, err := b.store.Siblings(b.path[idx])
+	if err != nil {
+		return err
+	}
+	if len(siblings) < 2 {
+		return fmt.Errorf("no alternate branches at message %d", idx)
+	}
+	options := make([]huh.Option[int64], 0, len(siblings))
+	for _, s := range siblings {
+		options = append(options, huh.NewOption(fmt.Sprintf("%d: %s — %.60s", s.ID, s.Message.Role, printMessage(s.Message)), s.ID))
+	}
+	var chosen int64
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[int64]().Title("branch").Options(options...).Value(&chosen),
+	)).Run(); err != nil {
+		return err
+	}
+	leaf, err := b.store.Leaf(chosen)
+	if err != nil {
+		return err
+	}
+	if err := b.store.SetHead(b.conversationID, leaf); err != nil {
+		return err
+	}
+	return b.hydrate()
+}
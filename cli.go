@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/huh"
+)
+
+// main dispatches to a conversation subcommand. `pinky` with no subcommand
+// shows the conversations picker, letting the user resume a prior chat or
+// start a new one.
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "new":
+			runNew(args[1:])
+			return
+		case "reply":
+			runReply(args[1:])
+			return
+		case "view":
+			runView(args[1:])
+			return
+		case "ls":
+			runList(args[1:])
+			return
+		case "rm":
+			runRemove(args[1:])
+			return
+		}
+	}
+	runPicker()
+}
+
+// runPicker offers a huh selector over existing conversations, falling
+// through to a new one when none exist or "new conversation" is chosen.
+func runPicker() {
+	store := openDefaultStore()
+	conversations, err := store.List()
+	store.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(conversations) == 0 {
+		runNew(nil)
+		return
+	}
+
+	options := make([]huh.Option[int64], 0, len(conversations)+1)
+	options = append(options, huh.NewOption("new conversation", int64(0)))
+	for _, c := range conversations {
+		title := c.Title
+		if title == "" {
+			title = fmt.Sprintf("conversation %d", c.ID)
+		}
+		options = append(options, huh.NewOption(title, c.ID))
+	}
+	var id int64
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[int64]().Title("conversation").Options(options...).Value(&id),
+	)).Run(); err != nil {
+		log.Fatal(err)
+	}
+	if id == 0 {
+		runNew(nil)
+		return
+	}
+	runReply([]string{strconv.FormatInt(id, 10)})
+}
+
+func openDefaultStore() *Store {
+	store, err := OpenStore(DefaultStorePath())
+	if err != nil {
+		log.Fatal(err)
+	}
+	return store
+}
+
+// agentFlag registers the -a/--agent flag shared by subcommands that start
+// or resume a session.
+func agentFlag(fs *flag.FlagSet) *string {
+	var name string
+	fs.StringVar(&name, "agent", "", "named agent to run (see ~/.config/pinky/agents.yaml)")
+	fs.StringVar(&name, "a", "", "shorthand for -agent")
+	return &name
+}
+
+func applyAgent(brain *Brain, name string) {
+	if name == "" {
+		return
+	}
+	agents, err := LoadAgents(DefaultAgentConfigPath())
+	if err != nil {
+		log.Fatal(err)
+	}
+	agent, ok := agents[name]
+	if !ok {
+		log.Fatalf("no such agent %q", name)
+	}
+	// Connect the agent's MCP servers before UseAgent filters Brain down to
+	// agent.Tools — otherwise any MCP tool name listed there doesn't exist
+	// in defaultTools yet and is silently dropped.
+	if err := ConnectMCPServers(context.Background(), defaultTools, &agent); err != nil {
+		log.Fatal(err)
+	}
+	brain.UseAgent(&agent)
+}
+
+func parseConversationID(fs *flag.FlagSet, usage string) int64 {
+	if fs.NArg() != 1 {
+		log.Fatal(usage)
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		log.Fatalf("invalid conversation id %q", fs.Arg(0))
+	}
+	return id
+}
+
+// runNew starts a brand new, persisted conversation.
+func runNew(args []string) {
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	agentName := agentFlag(fs)
+	fs.Parse(args)
+
+	store := openDefaultStore()
+	defer store.Close()
+	defer defaultTools.Close()
+	backend := os.Getenv("PINKY_BACKEND")
+	brain, err := NewBrain(defaultModelFor(backend), backend, 0, store, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	applyAgent(brain, *agentName)
+	if err := brain.Start(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runReply hydrates an existing conversation and continues it.
+func runReply(args []string) {
+	fs := flag.NewFlagSet("reply", flag.ExitOnError)
+	agentName := agentFlag(fs)
+	fs.Parse(args)
+	id := parseConversationID(fs, "usage: pinky reply <id>")
+
+	store := openDefaultStore()
+	defer store.Close()
+	defer defaultTools.Close()
+	backend := os.Getenv("PINKY_BACKEND")
+	brain, err := NewBrain(defaultModelFor(backend), backend, 0, store, id)
+	if err != nil {
+		log.Fatal(err)
+	}
+	applyAgent(brain, *agentName)
+	if err := brain.Start(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runView prints a stored conversation without resuming it.
+func runView(args []string) {
+	fs := flag.NewFlagSet("view", flag.ExitOnError)
+	fs.Parse(args)
+	id := parseConversationID(fs, "usage: pinky view <id>")
+
+	store := openDefaultStore()
+	defer store.Close()
+	history, _, err := store.History(id)
+	if err != nil {
+		log.Fatal(err)
+	}
+	(&Brain{history: history}).show()
+}
+
+// runList prints every stored conversation.
+func runList(args []string) {
+	fs := flag.NewFlagSet("ls", flag.ExitOnError)
+	fs.Parse(args)
+
+	store := openDefaultStore()
+	defer store.Close()
+	conversations, err := store.List()
+	if err != nil {
+		log.Fatal(err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tMODEL\tBACKEND\tCREATED")
+	for _, c := range conversations {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", c.ID, c.Title, c.Model, c.Backend, c.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+}
+
+// runRemove deletes a stored conversation and its messages.
+func runRemove(args []string) {
+	fs := flag.NewFlagSet("rm", flag.ExitOnError)
+	fs.Parse(args)
+	id := parseConversationID(fs, "usage: pinky rm <id>")
+
+	store := openDefaultStore()
+	defer store.Close()
+	if err := store.Delete(id); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/huh"
+	"github.com/ollama/ollama/api"
+)
+
+// confirmCall prompts the user to approve, edit, deny, or always-allow a
+// pending tool call, honoring any policy already recorded on defaultTools.
+// It returns the (possibly edited) call and whether it should run.
+func (b *Brain) confirmCall(c api.ToolCall) (api.ToolCall, bool, error) {
+	switch defaultTools.Policy(c.Function.Name) {
+	case PolicyAlways:
+		return c, true, nil
+	case PolicyNever:
+		return c, false, nil
+	}
+
+	rendered, err := json.MarshalIndent(c.Function, "", "  ")
+	if err != nil {
+		return c, false, err
+	}
+	if md, err := glamour.Render("# tool call: "+c.Function.Name+"\n```json\n"+string(rendered)+"\n```", "dark"); err == nil {
+		fmt.Println(md)
+	} else {
+		fmt.Println(string(rendered))
+	}
+
+	action := "approve"
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().Title("run this tool call?").Options(
+			huh.NewOption("approve", "approve"),
+			huh.NewOption("edit arguments", "edit"),
+			huh.NewOption("deny", "deny"),
+			huh.NewOption("always allow "+c.Function.Name, "always"),
+		).Value(&action),
+	)).Run(); err != nil {
+		return c, false, err
+	}
+
+	switch action {
+	case "edit":
+		raw, err := json.MarshalIndent(c.Function.Arguments, "", "  ")
+		if err != nil {
+			return c, false, err
+		}
+		edited, err := editInEditor(string(raw))
+		if err != nil {
+			return c, false, err
+		}
+		var args api.ToolCallFunctionArguments
+		if err := json.Unmarshal([]byte(edited), &args); err != nil {
+			return c, false, fmt.Errorf("confirmCall: invalid edited arguments: %w", err)
+		}
+		c.Function.Arguments = args
+		return c, true, nil
+	case "deny":
+		return c, false, nil
+	case "always":
+		b.persistToolPolicy(c.Function.Name, PolicyAlways)
+		return c, true, nil
+	default:
+		return c, true, nil
+	}
+}
+
+// persistToolPolicy records policy for tool both on the in-memory
+// defaultTools manager and, if the active agent came from the config file,
+// back into that agent's ToolPolicies so it survives future sessions.
+func (b *Brain) persistToolPolicy(tool string, policy ToolPolicy) {
+	defaultTools.SetPolicy(tool, policy)
+	if b.agent == nil {
+		return
+	}
+	if b.agent.ToolPolicies == nil {
+		b.agent.ToolPolicies = make(map[string]string)
+	}
+	b.agent.ToolPolicies[tool] = string(policy)
+	path := DefaultAgentConfigPath()
+	agents, err := LoadAgents(path)
+	if err != nil {
+		return
+	}
+	agents[b.agent.Name] = *b.agent
+	SaveAgents(path, agents)
+}
@@ -3,23 +3,16 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/huh"
-	"github.com/charmbracelet/huh/spinner"
 	"github.com/ollama/ollama/api"
 )
 
-type Tool interface {
-	Name() string
-	Description() api.Tool
-	Run(context.Context, api.ToolCallFunction) (api.Message, error)
-}
-
 func printMessage(m api.Message) string {
 	content := m.Content
 	calls := make([]string, 0, len(m.ToolCalls))
@@ -35,24 +28,152 @@ func printMessage(m api.Message) string {
 }
 
 type Brain struct {
-	model   string
-	limit   int
-	client  *api.Client
-	history []api.Message
-	tools   map[string]Tool
+	model          string
+	limit          int
+	backend        string
+	provider       ChatCompletionProvider
+	history        []api.Message
+	path           []int64         // store message id behind each entry in history, parallel to it
+	toolNames      map[string]bool // nil means every tool registered on defaultTools
+	agent          *Agent
+	store          *Store
+	conversationID int64
+	lastMessageID  int64
+	titled         bool
+}
+
+// NewBrain constructs a Brain backed by the ChatCompletionProvider named by
+// backend ("ollama", "openai", "anthropic", "google"). An empty backend
+// defaults to Ollama, matching the tool's original behavior.
+//
+// If store is non-nil, every message appended to history is persisted.
+// conversationID of 0 starts a fresh conversation; any other value hydrates
+// history from that existing conversation.
+func NewBrain(model, backend string, limit int, store *Store, conversationID int64) (*Brain, error) {
+	provider, err := NewProvider(backend)
+	if err != nil {
+		return nil, err
+	}
+	b := &Brain{
+		model:    model,
+		limit:    limit,
+		backend:  backend,
+		provider: provider,
+		store:    store,
+	}
+	if store == nil {
+		return b, nil
+	}
+	if conversationID == 0 {
+		id, err := store.NewConversation(model, backend)
+		if err != nil {
+			return nil, err
+		}
+		b.conversationID = id
+		return b, nil
+	}
+	b.conversationID = conversationID
+	b.titled = true
+	if err := b.hydrate(); err != nil {
+		return nil, err
+	}
+	return b, nil
 }
 
-func NewBrain(model string, limit int) (*Brain, error) {
-	client, err := api.ClientFromEnvironment()
-	return &Brain{
-		model:  model,
-		limit:  limit,
-		client: client,
-		tools:  defaultTools,
-	}, err
+// hydrate refreshes history and path from the store's current root-to-head
+// path for this conversation, e.g. after forking or switching branches.
+func (b *Brain) hydrate() error {
+	path, err := b.store.Path(b.conversationID)
+	if err != nil {
+		return err
+	}
+	b.history = make([]api.Message, len(path))
+	b.path = make([]int64, len(path))
+	for i, row := range path {
+		b.history[i] = row.Message
+		b.path[i] = row.ID
+	}
+	if len(path) > 0 {
+		b.lastMessageID = path[len(path)-1].ID
+	}
+	return nil
+}
+
+// appendMessage records m in history and, when a store is attached,
+// persists it as a child of the previous message.
+func (b *Brain) appendMessage(m api.Message) error {
+	b.history = append(b.history, m)
+	if b.store == nil {
+		return nil
+	}
+	id, err := b.store.AppendMessage(b.conversationID, b.lastMessageID, m)
+	if err != nil {
+		return err
+	}
+	b.lastMessageID = id
+	b.path = append(b.path, id)
+	return nil
+}
+
+// titleFromFirstReply summarises the first assistant reply of a new
+// conversation into a short title, stored alongside the conversation row.
+func (b *Brain) titleFromFirstReply(ctx context.Context, reply api.Message) {
+	if b.store == nil || b.titled {
+		return
+	}
+	b.titled = true
+	summary, err := b.provider.Chat(ctx, ChatRequest{
+		Model: b.model,
+		Messages: []api.Message{{
+			Role:    "user",
+			Content: "Summarize the following reply as a short title, five words or fewer, with no punctuation:\n\n" + reply.Content,
+		}},
+	})
+	if err != nil {
+		return
+	}
+	b.store.SetTitle(b.conversationID, strings.TrimSpace(summary.Content))
+}
+
+// UseAgent restricts the Brain to the tool subset and model override
+// declared by agent. Passing nil restores the full default tool set. Any
+// always/never policies recorded on agent.ToolPolicies (see
+// persistToolPolicy in confirm.go) are restored onto defaultTools so a
+// user's past "always allow" choices aren't re-prompted this session.
+func (b *Brain) UseAgent(agent *Agent) {
+	b.agent = agent
+	b.toolNames = toolNamesFor(agent)
+	if agent == nil {
+		return
+	}
+	if agent.Model != "" {
+		b.model = agent.Model
+	}
+	for name, policy := range agent.ToolPolicies {
+		defaultTools.SetPolicy(name, ToolPolicy(policy))
+	}
 }
 
 func (b *Brain) prompt() error {
+	if b.store != nil && len(b.history) > 0 {
+		action := "continue"
+		if err := huh.NewForm(huh.NewGroup(
+			huh.NewSelect[string]().Title("action").Options(
+				huh.NewOption("continue", "continue"),
+				huh.NewOption("edit a message", "edit"),
+				huh.NewOption("switch branch", "switch"),
+			).Value(&action),
+		)).Run(); err != nil {
+			return err
+		}
+		switch action {
+		case "edit":
+			return b.editAndFork(context.Background())
+		case "switch":
+			return b.switchBranch(context.Background())
+		}
+	}
+
 	var content string
 	fields := make([]huh.Field, 0, len(b.history))
 	for _, m := range b.history {
@@ -71,11 +192,10 @@ func (b *Brain) prompt() error {
 	).Run(); err != nil {
 		return err
 	}
-	b.history = append(b.history, api.Message{
+	return b.appendMessage(api.Message{
 		Role:    "user",
 		Content: content,
 	})
-	return nil
 }
 
 func (b *Brain) show() {
@@ -91,52 +211,76 @@ func (b *Brain) show() {
 	}
 }
 
-func (b *Brain) request() *api.ChatRequest {
-	tools := make(api.Tools, 0, len(b.tools))
-	for _, t := range b.tools {
-		tools = append(tools, t.Description())
-	}
-	think := true
-	stream := false
-	return &api.ChatRequest{
+func (b *Brain) request(onToken func(string)) ChatRequest {
+	return ChatRequest{
 		Model:    b.model,
 		Messages: b.history,
-		Tools:    tools,
-		Think:    &think,
-		Stream:   &stream,
+		Tools:    defaultTools.Functions(b.toolNames),
+		Think:    true,
+		OnToken:  onToken,
+		Grammar:  envOr("PINKY_GRAMMAR", "") == "true",
 	}
 }
 
 func (b *Brain) callTools(ctx context.Context, calls []api.ToolCall) error {
 	responses := make([]api.Message, len(calls))
 	for i, c := range calls {
-		tool, exist := b.tools[c.Function.Name]
+		t, exist := defaultTools.Lookup(c.Function.Name, b.toolNames)
 		if !exist {
 			return fmt.Errorf("called non-exist tool %q", c.Function.Name)
 		}
-		res, err := tool.Run(ctx, c.Function)
+		c, approved, err := b.confirmCall(c)
+		if err != nil {
+			return err
+		}
+		if !approved {
+			responses[i] = api.Message{Role: c.Function.Name, Content: `{"error":"user denied call"}`}
+			continue
+		}
+		res, err := t.Run(ctx, c.Function)
 		if err != nil {
 			return err
 		}
 		responses[i] = res
 	}
-	b.history = append(b.history, responses...)
+	for _, res := range responses {
+		if err := b.appendMessage(res); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (b *Brain) chat(ctx context.Context) error {
-	var message api.Message
-	if err := b.client.Chat(ctx, b.request(), func(cr api.ChatResponse) error {
-		message = cr.Message
-		return nil
-	}); err != nil {
+// chat asks the provider for the next reply, tracking every token it
+// streams so that a mid-stream Ctrl-C (runStreaming cancels ctx, see
+// stream.go) can still be recorded: rather than losing the partial reply,
+// it's appended to history truncated at the point of cancellation. Not
+// every provider streams incrementally (see provider_openai.go et al.), so
+// a Ctrl-C during a non-streaming provider's blocking call surfaces here
+// with no partial content at all; either way, cancellation is a clean stop,
+// not an error to propagate.
+func (b *Brain) chat(ctx context.Context, onToken func(string)) error {
+	var partial strings.Builder
+	message, err := b.provider.Chat(ctx, b.request(func(tok string) {
+		partial.WriteString(tok)
+		if onToken != nil {
+			onToken(tok)
+		}
+	}))
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return b.appendMessage(api.Message{Role: "assistant", Content: partial.String()})
+		}
+		return err
+	}
+	if err := b.appendMessage(message); err != nil {
 		return err
 	}
-	b.history = append(b.history, message)
+	b.titleFromFirstReply(ctx, message)
 	return nil
 }
 
-func (b *Brain) loop(ctx context.Context) error {
+func (b *Brain) loop(ctx context.Context, onToken func(string)) error {
 	latest := b.history[len(b.history)-1]
 	switch {
 	case len(latest.ToolCalls) != 0:
@@ -144,7 +288,7 @@ func (b *Brain) loop(ctx context.Context) error {
 			return err
 		}
 	default:
-		if err := b.chat(ctx); err != nil {
+		if err := b.chat(ctx, onToken); err != nil {
 			return err
 		}
 	}
@@ -159,9 +303,7 @@ func (b *Brain) start(ctx context.Context) error {
 				return err
 			}
 		} else {
-			if err := spinner.New().Title(
-				"thinking...",
-			).Context(ctx).ActionWithErr(b.loop).Run(); err != nil {
+			if err := runStreaming(ctx, b.loop); err != nil {
 				return err
 			}
 		}
@@ -171,11 +313,63 @@ func (b *Brain) start(ctx context.Context) error {
 }
 
 func (b *Brain) Start() error {
+	ctx := context.Background()
+	if len(b.history) != 0 {
+		// Resuming a conversation hydrated from the store: the prior
+		// messages already carry a system prompt and at least one user
+		// turn, so skip straight to the loop.
+		return b.start(ctx)
+	}
+	if b.agent == nil {
+		agents, _ := LoadAgents(DefaultAgentConfigPath())
+		if len(agents) > 0 {
+			options := make([]huh.Option[string], 0, len(agents)+1)
+			options = append(options, huh.NewOption("none", ""))
+			for name := range agents {
+				options = append(options, huh.NewOption(name, name))
+			}
+			var name string
+			if err := huh.NewForm(huh.NewGroup(
+				huh.NewSelect[string]().Title("agent").Options(options...).Value(&name),
+			)).Run(); err != nil {
+				return err
+			}
+			if agent, ok := agents[name]; ok {
+				// Connect MCP servers before UseAgent filters the Brain
+				// down to agent.Tools — see applyAgent in cli.go.
+				if err := ConnectMCPServers(ctx, defaultTools, &agent); err != nil {
+					return err
+				}
+				b.UseAgent(&agent)
+			}
+		}
+	}
+
 	var systemPrompt string
 	var prompt string
+	if b.agent != nil {
+		systemPrompt = b.agent.SystemPrompt
+	}
 	limit := strconv.Itoa(b.limit)
 	if err := huh.NewForm(
 		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("backend").
+				Options(
+					huh.NewOption("ollama", "ollama"),
+					huh.NewOption("openai", "openai"),
+					huh.NewOption("anthropic", "anthropic"),
+					huh.NewOption("google", "google"),
+				).
+				Value(&b.backend).
+				Validate(func(backend string) error {
+					provider, err := NewProvider(backend)
+					if err != nil {
+						return err
+					}
+					b.provider = provider
+					return nil
+				}),
 			huh.NewInput().Inline(true).Title("model").Value(&b.model),
 			huh.NewInput().Inline(true).Title("history limit").Value(&limit).Validate(func(s string) error {
 				l, err := strconv.Atoi(s)
@@ -196,21 +390,16 @@ func (b *Brain) Start() error {
 	).Run(); err != nil {
 		return err
 	}
-	b.history = append(
-		b.history,
-		api.Message{Role: "system", Content: systemPrompt},
-		api.Message{Role: "user", Content: prompt},
-	)
-
-	return b.start(context.Background())
-}
-
-func main() {
-	brain, err := NewBrain("qwen3", 0)
+	attachments, err := renderAttachments(ctx, b.agent)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	if err := brain.Start(); err != nil {
-		log.Fatal(err)
+	if err := b.appendMessage(api.Message{Role: "system", Content: systemPrompt}); err != nil {
+		return err
+	}
+	if err := b.appendMessage(api.Message{Role: "user", Content: attachments + prompt}); err != nil {
+		return err
 	}
+
+	return b.start(ctx)
 }
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+// mcpServer tracks one live MCP connection so its tools can be re-listed on
+// a tools/list_changed notification and its session closed on shutdown.
+type mcpServer struct {
+	name    string
+	session *mcp.ClientSession
+}
+
+// mcpTool proxies Run through the live session that produced it, under a
+// "serverName__toolName" name to avoid collisions between servers (and with
+// built-in tools) exposing the same tool name.
+type mcpTool struct {
+	server   string
+	function tool.Function
+	session  *mcp.ClientSession
+}
+
+func (t mcpTool) Name() string { return t.server + "__" + t.function.Name }
+
+func (t mcpTool) Description() tool.Function {
+	d := t.function
+	d.Name = t.Name()
+	return d
+}
+
+func (t mcpTool) Run(ctx context.Context, call api.ToolCallFunction) (api.Message, error) {
+	res, err := t.session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      t.function.Name,
+		Arguments: map[string]any(call.Arguments),
+	})
+	if err != nil {
+		return api.Message{}, err
+	}
+	return api.Message{Role: t.Name(), Content: contentToText(res.Content)}, nil
+}
+
+// contentToText flattens MCP result content blocks (text, image, ...) into
+// the plain-text shape api.Message.Content expects.
+func contentToText(blocks []mcp.Content) string {
+	var out strings.Builder
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *mcp.TextContent:
+			out.WriteString(b.Text)
+		case *mcp.ImageContent:
+			fmt.Fprintf(&out, "[image: %s]", b.MIMEType)
+		default:
+			fmt.Fprintf(&out, "[unsupported content block %T]", block)
+		}
+		out.WriteString("\n")
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// mcpTransport builds the stdio or streamable-HTTP transport cfg describes.
+// Exactly one of cfg.Command or cfg.URL is expected to be set.
+func mcpTransport(cfg MCPServerConfig) (mcp.Transport, error) {
+	switch {
+	case cfg.Command != "":
+		return &mcp.CommandTransport{Command: exec.Command(cfg.Command, cfg.Args...)}, nil
+	case cfg.URL != "":
+		return &mcp.StreamableClientTransport{Endpoint: cfg.URL, HTTPClient: headerInjectingClient(cfg.Headers)}, nil
+	default:
+		return nil, fmt.Errorf("mcp server %q: need either command or url", cfg.Name)
+	}
+}
+
+// headerInjectingClient returns nil when headers is empty (letting the SDK
+// fall back to its default client), otherwise an *http.Client that adds
+// headers to every outgoing request.
+func headerInjectingClient(headers map[string]string) *http.Client {
+	if len(headers) == 0 {
+		return nil
+	}
+	return &http.Client{Transport: headerInjectingTransport{headers: headers, base: http.DefaultTransport}}
+}
+
+type headerInjectingTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func (t headerInjectingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// AddMCPServer connects to an MCP server over transport, registers each of
+// its tools under the "name__toolName" prefix, and keeps the registration in
+// sync with tools/list_changed notifications until Close is called. A
+// second call for a name already registered is a no-op: it leaves the
+// existing session (and its tools) untouched rather than opening a second,
+// unreachable session under the same name.
+func (m *toolManager) AddMCPServer(ctx context.Context, name string, transport mcp.Transport) error {
+	m.mu.Lock()
+	_, exists := m.mcpServers[name]
+	m.mu.Unlock()
+	if exists {
+		return nil
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "pinky", Version: "0.1.0"}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(ctx context.Context, req *mcp.ToolListChangedRequest) {
+			m.reloadMCPServer(ctx, name)
+		},
+	})
+	session, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		return fmt.Errorf("connecting to mcp server %q: %w", name, err)
+	}
+
+	m.mu.Lock()
+	m.mcpServers[name] = &mcpServer{name: name, session: session}
+	m.mu.Unlock()
+
+	return m.registerMCPTools(ctx, name, session)
+}
+
+// registerMCPTools lists tools on session and (re)registers them, replacing
+// any previous registrations under the same server prefix.
+func (m *toolManager) registerMCPTools(ctx context.Context, name string, session *mcp.ClientSession) error {
+	functions, err := FromMCPClient(ctx, session)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeMCPToolsLocked(name)
+	for _, function := range functions {
+		t := mcpTool{server: name, function: function, session: session}
+		m.tools[t.Name()] = t
+	}
+	return nil
+}
+
+// removeMCPToolsLocked drops every tool registered under server's prefix.
+// Callers must hold m.mu.
+func (m *toolManager) removeMCPToolsLocked(server string) {
+	prefix := server + "__"
+	for name := range m.tools {
+		if strings.HasPrefix(name, prefix) {
+			delete(m.tools, name)
+		}
+	}
+}
+
+// reloadMCPServer re-lists and re-registers a server's tools after it sends
+// a tools/list_changed notification. Errors are not fatal to the running
+// session: the previous registrations are left in place until the next
+// successful reload.
+func (m *toolManager) reloadMCPServer(ctx context.Context, name string) {
+	m.mu.Lock()
+	server, ok := m.mcpServers[name]
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+	m.registerMCPTools(ctx, name, server.session)
+}
+
+// Close shuts down every live MCP session. Call it once when the TUI exits.
+func (m *toolManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var firstErr error
+	for _, server := range m.mcpServers {
+		if err := server.session.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ConnectMCPServers connects every MCP server declared on agent, leaving
+// already-connected servers untouched. A nil agent is a no-op.
+func ConnectMCPServers(ctx context.Context, manager *toolManager, agent *Agent) error {
+	if agent == nil {
+		return nil
+	}
+	for _, cfg := range agent.MCPServers {
+		transport, err := mcpTransport(cfg)
+		if err != nil {
+			return err
+		}
+		if err := manager.AddMCPServer(ctx, cfg.Name, transport); err != nil {
+			return err
+		}
+	}
+	return nil
+}
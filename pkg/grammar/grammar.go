@@ -0,0 +1,159 @@
+// Package grammar translates pkg/tool's JSON-schema-ish Parameters into GBNF
+// (the grammar dialect llama.cpp/Ollama use to constrain token decoding),
+// so a model can be forced to emit well-formed tool-call JSON even when it
+// would otherwise produce malformed output.
+package grammar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+// primitives are the leaf rules every generated grammar shares.
+const primitives = `ws ::= [ \t\n]*
+string ::= "\"" ( [^"\\] | "\\" . )* "\""
+number ::= "-"? [0-9]+ ("." [0-9]+)?
+integer ::= "-"? [0-9]+
+boolean ::= "true" | "false"
+null ::= "null"
+`
+
+// FromParameters compiles p into a standalone GBNF grammar whose root rule
+// matches exactly the JSON object shapes p describes.
+func FromParameters(p tool.Parameters) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "root ::= %s\n", object(p.Properties, p.Required))
+	b.WriteString(primitives)
+	return b.String()
+}
+
+// FromFunctions combines the argument grammars of every tool into a single
+// top-level alternation plus a plain-text fallback — `root ::= tool_call_0 |
+// tool_call_1 | ... | plain_text` — the shape Ollama's options.grammar
+// expects so a reply can still be ordinary text when no tool applies.
+func FromFunctions(fns []tool.Function) string {
+	names := make([]string, 0, len(fns)+1)
+	var rules strings.Builder
+	for i, fn := range fns {
+		name := fmt.Sprintf("tool_call_%d", i)
+		names = append(names, name)
+		fmt.Fprintf(&rules, "%s ::= %s\n", name, toolCall(fn))
+	}
+	names = append(names, "plain_text")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "root ::= %s\n", strings.Join(names, " | "))
+	b.WriteString(rules.String())
+	b.WriteString(`plain_text ::= .*` + "\n")
+	b.WriteString(primitives)
+	return b.String()
+}
+
+// toolCall renders the `{"name": "...", "arguments": {...}}` shape a tool
+// call is decoded as.
+func toolCall(fn tool.Function) string {
+	return fmt.Sprintf(
+		`"{" ws "\"name\"" ws ":" ws "\"%s\"" ws "," ws "\"arguments\"" ws ":" ws %s ws "}"`,
+		fn.Name, object(fn.Parameters.Properties, fn.Parameters.Required),
+	)
+}
+
+// object renders a GBNF expression matching a JSON object with the given
+// properties. Required members are rendered first, always comma-joined.
+// Optional members follow, each in its own `( ws "," ws member )?` group:
+// that way skipping a member always skips its separating comma along with
+// it, rather than splicing a bare `ws "," ws` between independently-optional
+// members, which would legally accept `{"a":1,}` or `{,"b":2}` whenever a
+// neighboring group is omitted. With no required members, the body is an
+// alternation over which optional member comes first (that one without a
+// leading comma), each followed by the optional groups of those after it.
+func object(props map[string]*tool.Property, required []string) string {
+	if len(props) == 0 {
+		return `"{" ws "}"`
+	}
+	req := make(map[string]bool, len(required))
+	for _, r := range required {
+		req[r] = true
+	}
+
+	var requiredNames, optionalNames []string
+	for name := range props {
+		if req[name] {
+			requiredNames = append(requiredNames, name)
+		} else {
+			optionalNames = append(optionalNames, name)
+		}
+	}
+	sort.Strings(requiredNames)
+	sort.Strings(optionalNames)
+
+	member := func(name string) string {
+		return fmt.Sprintf(`"\"%s\"" ws ":" ws %s`, name, property(props[name]))
+	}
+
+	requiredMembers := make([]string, len(requiredNames))
+	for i, name := range requiredNames {
+		requiredMembers[i] = member(name)
+	}
+	requiredPart := strings.Join(requiredMembers, ` ws "," ws `)
+
+	// Built from the last member back: before member i is added,
+	// optionalPart holds the independent comma-led groups of the members
+	// after it. Each member's leading comma lives inside its own "(...)?"
+	// group, never spliced in unconditionally from the outside.
+	var optionalPart string
+	var firstAlts []string
+	for i := len(optionalNames) - 1; i >= 0; i-- {
+		m := member(optionalNames[i])
+		if requiredPart == "" {
+			firstAlts = append([]string{strings.TrimSpace(m + " " + optionalPart)}, firstAlts...)
+		}
+		optionalPart = strings.TrimSpace(`( ws "," ws ` + m + ` )? ` + optionalPart)
+	}
+	if requiredPart == "" && len(firstAlts) > 0 {
+		optionalPart = "( " + strings.Join(firstAlts, " | ") + " )?"
+	}
+
+	body := requiredPart
+	switch {
+	case body == "":
+		body = optionalPart
+	case optionalPart != "":
+		body += " " + optionalPart
+	}
+	return fmt.Sprintf(`"{" ws %s ws "}"`, body)
+}
+
+// property renders a GBNF expression matching a single property's value.
+func property(p *tool.Property) string {
+	if len(p.Enum) > 0 {
+		alts := make([]string, len(p.Enum))
+		for i, e := range p.Enum {
+			alts[i] = fmt.Sprintf(`"\"%s\""`, e)
+		}
+		return "( " + strings.Join(alts, " | ") + " )"
+	}
+	switch p.Type {
+	case "object":
+		return object(p.Properties, p.Required)
+	case "array":
+		if p.Items == nil {
+			return `"[" ws "]"`
+		}
+		item := property(p.Items)
+		return fmt.Sprintf(`"[" ws ( %s ( ws "," ws %s )* )? ws "]"`, item, item)
+	case "integer":
+		return "integer"
+	case "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,147 @@
+package grammar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+func TestFromParametersRequiredAndOptional(t *testing.T) {
+	params := tool.Parameters{
+		Type:     "object",
+		Required: []string{"path"},
+		Properties: map[string]*tool.Property{
+			"path":  {Type: "string"},
+			"depth": {Type: "integer"},
+		},
+	}
+	g := FromParameters(params)
+
+	if !strings.Contains(g, `"\"path\"" ws ":" ws string`) {
+		t.Errorf("required property %q not rendered unwrapped:\n%s", "path", g)
+	}
+	if !strings.Contains(g, `( ws "," ws "\"depth\"" ws ":" ws integer )?`) {
+		t.Errorf("optional property %q not wrapped as optional with its own leading comma:\n%s", "depth", g)
+	}
+}
+
+func TestFromParametersNestedObject(t *testing.T) {
+	params := tool.Parameters{
+		Type:     "object",
+		Required: []string{"edit"},
+		Properties: map[string]*tool.Property{
+			"edit": {
+				Type:     "object",
+				Required: []string{"start_line"},
+				Properties: map[string]*tool.Property{
+					"start_line":  {Type: "integer"},
+					"replacement": {Type: "string"},
+				},
+			},
+		},
+	}
+	g := FromParameters(params)
+
+	if !strings.Contains(g, `"\"start_line\"" ws ":" ws integer`) {
+		t.Errorf("nested required field not rendered:\n%s", g)
+	}
+	if !strings.Contains(g, `( ws "," ws "\"replacement\"" ws ":" ws string )?`) {
+		t.Errorf("nested optional field not wrapped as optional with its own leading comma:\n%s", g)
+	}
+}
+
+func TestFromParametersArrayOfObjects(t *testing.T) {
+	params := tool.Parameters{
+		Type:     "object",
+		Required: []string{"edits"},
+		Properties: map[string]*tool.Property{
+			"edits": {
+				Type: "array",
+				Items: &tool.Property{
+					Type:     "object",
+					Required: []string{"path"},
+					Properties: map[string]*tool.Property{
+						"path": {Type: "string"},
+					},
+				},
+			},
+		},
+	}
+	g := FromParameters(params)
+
+	if !strings.Contains(g, `"[" ws (`) {
+		t.Errorf("array property not rendered as a GBNF list:\n%s", g)
+	}
+	if !strings.Contains(g, `"\"path\"" ws ":" ws string`) {
+		t.Errorf("array item object fields not rendered:\n%s", g)
+	}
+}
+
+func TestFromParametersEnum(t *testing.T) {
+	params := tool.Parameters{
+		Type:     "object",
+		Required: []string{"unit"},
+		Properties: map[string]*tool.Property{
+			"unit": {Type: "string", Enum: []string{"celsius", "fahrenheit"}},
+		},
+	}
+	g := FromParameters(params)
+
+	if !strings.Contains(g, `( "\"celsius\"" | "\"fahrenheit\"" )`) {
+		t.Errorf("enum not rendered as an alternation:\n%s", g)
+	}
+}
+
+func TestFromFunctionsCombinesToolsWithPlainTextFallback(t *testing.T) {
+	fns := []tool.Function{
+		{Name: "web_search", Parameters: tool.Parameters{
+			Required:   []string{"search_term"},
+			Properties: map[string]*tool.Property{"search_term": {Type: "string"}},
+		}},
+		{Name: "read_file", Parameters: tool.Parameters{
+			Required:   []string{"path"},
+			Properties: map[string]*tool.Property{"path": {Type: "string"}},
+		}},
+	}
+	g := FromFunctions(fns)
+
+	if !strings.Contains(g, "root ::= tool_call_0 | tool_call_1 | plain_text") {
+		t.Errorf("root alternation missing expected rules:\n%s", g)
+	}
+	if !strings.Contains(g, `"\"name\"" ws ":" ws "\"web_search\""`) {
+		t.Errorf("tool_call_0 does not pin the tool name:\n%s", g)
+	}
+	if !strings.Contains(g, `"\"name\"" ws ":" ws "\"read_file\""`) {
+		t.Errorf("tool_call_1 does not pin the tool name:\n%s", g)
+	}
+}
+
+func TestFromParametersRejectsTrailingCommaBeforeOptionalMember(t *testing.T) {
+	params := tool.Parameters{
+		Type:     "object",
+		Required: []string{"path"},
+		Properties: map[string]*tool.Property{
+			"path":  {Type: "string"},
+			"depth": {Type: "integer"},
+		},
+	}
+	g := FromParameters(params)
+
+	if !acceptsExactly(g, "root", `{"path":"a"}`) {
+		t.Errorf("omitting the optional member should be accepted:\n%s", g)
+	}
+	if !acceptsExactly(g, "root", `{"path":"a","depth":1}`) {
+		t.Errorf("including the optional member should be accepted:\n%s", g)
+	}
+	if acceptsExactly(g, "root", `{"path":"a",}`) {
+		t.Errorf("a trailing comma before the closing brace should be rejected:\n%s", g)
+	}
+}
+
+func TestFromParametersEmptyObject(t *testing.T) {
+	g := FromParameters(tool.Parameters{Type: "object"})
+	if !strings.Contains(g, `root ::= "{" ws "}"`) {
+		t.Errorf("empty parameters should render an empty object:\n%s", g)
+	}
+}
@@ -0,0 +1,381 @@
+package grammar
+
+import (
+	"sort"
+	"strings"
+)
+
+// A small backtracking recognizer for the restricted GBNF subset this
+// package generates (quoted literals, "[...]" classes, ".", rule
+// references, "|", sequences, and "?"/"*"/"+" postfix), used by tests to
+// confirm a sample JSON string is actually accepted by a generated
+// grammar — not just that the grammar text contains expected fragments.
+
+type gExpr interface {
+	// match returns every input position reachable after matching the
+	// expression starting at pos, against the given rule set.
+	match(g *gGrammar, input []rune, pos int) []int
+}
+
+type gGrammar struct {
+	rules map[string]gExpr
+}
+
+func uniqueSorted(positions []int) []int {
+	seen := make(map[int]bool, len(positions))
+	out := make([]int, 0, len(positions))
+	for _, p := range positions {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+type gLiteral string
+
+func (l gLiteral) match(_ *gGrammar, input []rune, pos int) []int {
+	s := []rune(string(l))
+	if pos+len(s) > len(input) {
+		return nil
+	}
+	for i, r := range s {
+		if input[pos+i] != r {
+			return nil
+		}
+	}
+	return []int{pos + len(s)}
+}
+
+type gDot struct{}
+
+func (gDot) match(_ *gGrammar, input []rune, pos int) []int {
+	if pos >= len(input) {
+		return nil
+	}
+	return []int{pos + 1}
+}
+
+// gClass is a GBNF "[...]" character class; ranges are pairs of runes.
+type gClass struct {
+	negate bool
+	runes  []rune
+	ranges [][2]rune
+}
+
+func (c gClass) matchesRune(r rune) bool {
+	for _, x := range c.runes {
+		if r == x {
+			return !c.negate
+		}
+	}
+	for _, rg := range c.ranges {
+		if r >= rg[0] && r <= rg[1] {
+			return !c.negate
+		}
+	}
+	return c.negate
+}
+
+func (c gClass) match(_ *gGrammar, input []rune, pos int) []int {
+	if pos >= len(input) || !c.matchesRune(input[pos]) {
+		return nil
+	}
+	return []int{pos + 1}
+}
+
+type gRef string
+
+func (r gRef) match(g *gGrammar, input []rune, pos int) []int {
+	rule, ok := g.rules[string(r)]
+	if !ok {
+		panic("grammar: unknown rule " + string(r))
+	}
+	return rule.match(g, input, pos)
+}
+
+type gSeq []gExpr
+
+func (s gSeq) match(g *gGrammar, input []rune, pos int) []int {
+	positions := []int{pos}
+	for _, e := range s {
+		next := make([]int, 0, len(positions))
+		for _, p := range positions {
+			next = append(next, e.match(g, input, p)...)
+		}
+		positions = uniqueSorted(next)
+		if len(positions) == 0 {
+			return nil
+		}
+	}
+	return positions
+}
+
+type gAlt []gExpr
+
+func (a gAlt) match(g *gGrammar, input []rune, pos int) []int {
+	var out []int
+	for _, e := range a {
+		out = append(out, e.match(g, input, pos)...)
+	}
+	return uniqueSorted(out)
+}
+
+type gOpt struct{ e gExpr }
+
+func (o gOpt) match(g *gGrammar, input []rune, pos int) []int {
+	out := append([]int{pos}, o.e.match(g, input, pos)...)
+	return uniqueSorted(out)
+}
+
+type gStar struct{ e gExpr }
+
+func (s gStar) match(g *gGrammar, input []rune, pos int) []int {
+	visited := map[int]bool{pos: true}
+	frontier := []int{pos}
+	for len(frontier) > 0 {
+		var next []int
+		for _, p := range frontier {
+			for _, p2 := range s.e.match(g, input, p) {
+				if p2 > p && !visited[p2] {
+					visited[p2] = true
+					next = append(next, p2)
+				}
+			}
+		}
+		frontier = next
+	}
+	out := make([]int, 0, len(visited))
+	for p := range visited {
+		out = append(out, p)
+	}
+	return out
+}
+
+type gPlus struct{ e gExpr }
+
+func (p gPlus) match(g *gGrammar, input []rune, pos int) []int {
+	return gSeq{p.e, gStar{p.e}}.match(g, input, pos)
+}
+
+// --- tokenizer/parser for one rule's right-hand side ---
+
+type gTok struct {
+	kind string // "lit", "class", "dot", "ident", "(", ")", "|", "?", "*", "+"
+	text string
+}
+
+func gTokenize(s string) []gTok {
+	var toks []gTok
+	r := []rune(s)
+	for i := 0; i < len(r); {
+		switch {
+		case r[i] == ' ' || r[i] == '\t':
+			i++
+		case r[i] == '"':
+			j := i + 1
+			var lit strings.Builder
+			for j < len(r) && r[j] != '"' {
+				if r[j] == '\\' && j+1 < len(r) {
+					lit.WriteRune(r[j+1])
+					j += 2
+					continue
+				}
+				lit.WriteRune(r[j])
+				j++
+			}
+			toks = append(toks, gTok{"lit", lit.String()})
+			i = j + 1
+		case r[i] == '[':
+			j := i + 1
+			for j < len(r) && r[j] != ']' {
+				if r[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			toks = append(toks, gTok{"class", string(r[i+1 : j])})
+			i = j + 1
+		case r[i] == '.':
+			toks = append(toks, gTok{"dot", "."})
+			i++
+		case r[i] == '(' || r[i] == ')' || r[i] == '|' || r[i] == '?' || r[i] == '*' || r[i] == '+':
+			toks = append(toks, gTok{string(r[i]), string(r[i])})
+			i++
+		default:
+			j := i
+			for j < len(r) && r[j] != ' ' && r[j] != '\t' && r[j] != '(' && r[j] != ')' && r[j] != '|' {
+				j++
+			}
+			toks = append(toks, gTok{"ident", string(r[i:j])})
+			i = j
+		}
+	}
+	return toks
+}
+
+func gParseClass(src string) gClass {
+	c := gClass{}
+	r := []rune(src)
+	i := 0
+	if i < len(r) && r[i] == '^' {
+		c.negate = true
+		i++
+	}
+	for i < len(r) {
+		ch := r[i]
+		if ch == '\\' && i+1 < len(r) {
+			switch r[i+1] {
+			case 't':
+				ch = '\t'
+			case 'n':
+				ch = '\n'
+			default:
+				ch = r[i+1]
+			}
+			i += 2
+		} else {
+			i++
+		}
+		if i < len(r) && r[i] == '-' && i+1 < len(r) && r[i-1] != '\\' {
+			end := r[i+1]
+			if end == '\\' && i+2 < len(r) {
+				switch r[i+2] {
+				case 't':
+					end = '\t'
+				case 'n':
+					end = '\n'
+				default:
+					end = r[i+2]
+				}
+				i += 3
+			} else {
+				i += 2
+			}
+			c.ranges = append(c.ranges, [2]rune{ch, end})
+			continue
+		}
+		c.runes = append(c.runes, ch)
+	}
+	return c
+}
+
+type gParser struct {
+	toks []gTok
+	pos  int
+}
+
+func (p *gParser) peek() *gTok {
+	if p.pos >= len(p.toks) {
+		return nil
+	}
+	return &p.toks[p.pos]
+}
+
+func (p *gParser) parseAlt() gExpr {
+	first := p.parseSeq()
+	alts := gAlt{first}
+	for {
+		t := p.peek()
+		if t == nil || t.kind != "|" {
+			break
+		}
+		p.pos++
+		alts = append(alts, p.parseSeq())
+	}
+	if len(alts) == 1 {
+		return alts[0]
+	}
+	return alts
+}
+
+func (p *gParser) parseSeq() gExpr {
+	var seq gSeq
+	for {
+		t := p.peek()
+		if t == nil || t.kind == ")" || t.kind == "|" {
+			break
+		}
+		seq = append(seq, p.parseAtom())
+	}
+	if len(seq) == 1 {
+		return seq[0]
+	}
+	return seq
+}
+
+func (p *gParser) parseAtom() gExpr {
+	t := p.peek()
+	var base gExpr
+	switch t.kind {
+	case "(":
+		p.pos++
+		base = p.parseAlt()
+		if t2 := p.peek(); t2 != nil && t2.kind == ")" {
+			p.pos++
+		}
+	case "lit":
+		p.pos++
+		base = gLiteral(t.text)
+	case "class":
+		p.pos++
+		base = gParseClass(t.text)
+	case "dot":
+		p.pos++
+		base = gDot{}
+	case "ident":
+		p.pos++
+		base = gRef(t.text)
+	default:
+		panic("grammar: unexpected token " + t.kind)
+	}
+	if t2 := p.peek(); t2 != nil {
+		switch t2.kind {
+		case "?":
+			p.pos++
+			return gOpt{base}
+		case "*":
+			p.pos++
+			return gStar{base}
+		case "+":
+			p.pos++
+			return gPlus{base}
+		}
+	}
+	return base
+}
+
+// parseGrammar parses a GBNF text (one "name ::= rhs" per line, the shape
+// this package always emits) into a rule set usable by gRef.match.
+func parseGrammar(text string) *gGrammar {
+	g := &gGrammar{rules: map[string]gExpr{}}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "::=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		p := &gParser{toks: gTokenize(parts[1])}
+		g.rules[name] = p.parseAlt()
+	}
+	return g
+}
+
+// acceptsExactly reports whether root, expanded against grammar, matches
+// input in its entirety (no leftover, no partial match).
+func acceptsExactly(grammar, root, input string) bool {
+	g := parseGrammar(grammar)
+	r := []rune(input)
+	for _, end := range gRef(root).match(g, r, 0) {
+		if end == len(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+// Package tool defines the vendor-agnostic shape pinky uses to describe and
+// invoke tools, independent of the main package so both the built-in
+// toolbox and MCP-imported tools can implement it without an import cycle.
+package tool
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ollama/ollama/api"
+)
+
+// Tool is anything pinky can register and let a model call.
+type Tool interface {
+	Name() string
+	Description() Function
+	Run(context.Context, api.ToolCallFunction) (api.Message, error)
+}
+
+// Function is the canonical, backend-agnostic description of a tool.
+// Providers translate it into their own wire format (OpenAI/Ollama
+// functions, Anthropic input_schema, Gemini functionDeclarations, ...).
+type Function struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Parameters  Parameters `json:"parameters"`
+}
+
+// ToolFunction renders f as an Ollama api.ToolFunction.
+func (f Function) ToolFunction() api.ToolFunction {
+	var function api.ToolFunction
+	b, _ := json.Marshal(&f)
+	json.Unmarshal(b, &function)
+	return function
+}
+
+// Tool renders f as an Ollama api.Tool, the same shape OpenAI's function
+// tools use.
+func (f Function) Tool() api.Tool {
+	return api.Tool{
+		Type:     "function",
+		Function: f.ToolFunction(),
+	}
+}
+
+type Parameters struct {
+	Type       string               `json:"type"`
+	Required   []string             `json:"required,omitempty"`
+	Properties map[string]*Property `json:"properties"`
+}
+
+// Property describes a single parameter. Properties and Required describe a
+// nested object's own fields when Type is "object"; Items describes the
+// element schema when Type is "array".
+type Property struct {
+	Type        string               `json:"type"`
+	Description string               `json:"description"`
+	Enum        []string             `json:"enum,omitempty"`
+	Properties  map[string]*Property `json:"properties,omitempty"`
+	Required    []string             `json:"required,omitempty"`
+	Items       *Property            `json:"items,omitempty"`
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// ChatRequest is the backend-agnostic shape of a chat completion request.
+// Providers translate it (and the canonical Functions describing available
+// tools) into whatever wire format their vendor expects.
+type ChatRequest struct {
+	Model    string
+	Messages []api.Message
+	Tools    []tool.Function
+	Think    bool
+	// OnToken, if set, is called with each incremental chunk of the
+	// assistant's reply as it streams in. Providers without native
+	// incremental streaming support call it once with the full reply.
+	OnToken func(string)
+	// Grammar, when true and the backend supports it, constrains decoding
+	// to a GBNF grammar derived from Tools so malformed tool-call JSON
+	// can't be emitted. See pkg/grammar.
+	Grammar bool
+}
+
+// ChatCompletionProvider is implemented once per LLM vendor. Chat sends a
+// ChatRequest and returns the assistant's reply, translating tool schemas
+// and messages to and from the provider's own representation.
+type ChatCompletionProvider interface {
+	Chat(ctx context.Context, req ChatRequest) (api.Message, error)
+}
+
+// NewProvider resolves a ChatCompletionProvider by backend name. An empty
+// name defaults to Ollama, preserving pinky's original behavior.
+func NewProvider(backend string) (ChatCompletionProvider, error) {
+	switch backend {
+	case "", "ollama":
+		return newOllamaProvider()
+	case "openai":
+		return newOpenAIProvider()
+	case "anthropic":
+		return newAnthropicProvider()
+	case "google", "gemini":
+		return newGoogleProvider()
+	default:
+		return nil, fmt.Errorf("unknown backend %q", backend)
+	}
+}
+
+// toolNameSet returns the set of tool names described by tools, used by
+// providers to recognize a tool-result message: Tool.Run sets Role to the
+// tool's own name (see tools.go), not a generic "tool" literal.
+func toolNameSet(tools []tool.Function) map[string]bool {
+	set := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		set[t.Name] = true
+	}
+	return set
+}
+
+// defaultModelFor returns the per-backend default model, overridable with
+// PINKY_MODEL.
+func defaultModelFor(backend string) string {
+	if model := envOr("PINKY_MODEL", ""); model != "" {
+		return model
+	}
+	switch backend {
+	case "openai":
+		return "gpt-4o-mini"
+	case "anthropic":
+		return "claude-sonnet-4-5"
+	case "google", "gemini":
+		return "gemini-2.0-flash"
+	default:
+		return "qwen3"
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+// anthropicProvider speaks the Anthropic Messages API. Anthropic pulls the
+// system prompt out of Messages and expects tool schemas under
+// `input_schema` rather than `parameters`.
+type anthropicProvider struct {
+	client anthropic.Client
+}
+
+func newAnthropicProvider() (ChatCompletionProvider, error) {
+	return &anthropicProvider{
+		client: anthropic.NewClient(option.WithAPIKey(envOr("ANTHROPIC_API_KEY", ""))),
+	}, nil
+}
+
+func (p *anthropicProvider) Chat(ctx context.Context, req ChatRequest) (api.Message, error) {
+	system, messages := splitSystemPrompt(req.Messages)
+	res, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     anthropic.Model(req.Model),
+		MaxTokens: 4096,
+		System:    []anthropic.TextBlockParam{{Text: system}},
+		Messages:  toAnthropicMessages(messages, req.Tools),
+		Tools:     toAnthropicTools(req.Tools),
+	})
+	if err != nil {
+		return api.Message{}, err
+	}
+	message := fromAnthropicMessage(res)
+	// Anthropic support doesn't stream incrementally yet; report the whole
+	// reply as a single chunk so callers can treat every backend uniformly.
+	if req.OnToken != nil && message.Content != "" {
+		req.OnToken(message.Content)
+	}
+	return message, nil
+}
+
+func splitSystemPrompt(messages []api.Message) (string, []api.Message) {
+	var system string
+	rest := make([]api.Message, 0, len(messages))
+	for _, m := range messages {
+		if m.Role == "system" {
+			system = m.Content
+			continue
+		}
+		rest = append(rest, m)
+	}
+	return system, rest
+}
+
+func toAnthropicTools(tools []tool.Function) []anthropic.ToolUnionParam {
+	out := make([]anthropic.ToolUnionParam, 0, len(tools))
+	for _, t := range tools {
+		var schema anthropic.ToolInputSchemaParam
+		b, _ := json.Marshal(t.Parameters)
+		json.Unmarshal(b, &schema)
+		out = append(out, anthropic.ToolUnionParam{
+			OfTool: &anthropic.ToolParam{
+				Name:        t.Name,
+				Description: anthropic.String(t.Description),
+				InputSchema: schema,
+			},
+		})
+	}
+	return out
+}
+
+// toAnthropicMessages translates history into Anthropic's message shape. A
+// tool-result message is identified by Role matching one of tools' names
+// (the convention every Tool.Run follows, see tools.go), not a generic
+// "tool" literal. Tool calls carry no id anywhere else in the pipeline, so
+// an assistant message's tool_use blocks are assigned synthetic,
+// request-local ids that the immediately-following tool-result messages are
+// paired with in order, satisfying Anthropic's tool_use_id matching within
+// this request. Anthropic requires strict user/assistant alternation, so
+// every contiguous run of tool-result messages (one per tool call in the
+// preceding assistant turn) is batched into a single user message with one
+// ToolResultBlock per result, not one user message per result.
+func toAnthropicMessages(messages []api.Message, tools []tool.Function) []anthropic.MessageParam {
+	toolNames := toolNameSet(tools)
+	out := make([]anthropic.MessageParam, 0, len(messages))
+	var pendingIDs []string
+	var pendingResults []anthropic.ContentBlockParamUnion
+	flushResults := func() {
+		if len(pendingResults) > 0 {
+			out = append(out, anthropic.NewUserMessage(pendingResults...))
+			pendingResults = nil
+		}
+	}
+	for _, m := range messages {
+		switch {
+		case m.Role == "assistant":
+			flushResults()
+			if len(m.ToolCalls) == 0 {
+				out = append(out, anthropic.NewAssistantMessage(anthropic.NewTextBlock(m.Content)))
+				pendingIDs = nil
+				continue
+			}
+			blocks := make([]anthropic.ContentBlockParamUnion, len(m.ToolCalls))
+			pendingIDs = make([]string, len(m.ToolCalls))
+			for i, c := range m.ToolCalls {
+				id := fmt.Sprintf("toolu_%d", i)
+				blocks[i] = anthropic.NewToolUseBlock(id, c.Function.Arguments, c.Function.Name)
+				pendingIDs[i] = id
+			}
+			out = append(out, anthropic.NewAssistantMessage(blocks...))
+		case toolNames[m.Role]:
+			var id string
+			if len(pendingIDs) > 0 {
+				id, pendingIDs = pendingIDs[0], pendingIDs[1:]
+			}
+			pendingResults = append(pendingResults, anthropic.NewToolResultBlock(id, m.Content, false))
+		default:
+			flushResults()
+			out = append(out, anthropic.NewUserMessage(anthropic.NewTextBlock(m.Content)))
+			pendingIDs = nil
+		}
+	}
+	flushResults()
+	return out
+}
+
+func fromAnthropicMessage(res *anthropic.Message) api.Message {
+	var content string
+	calls := make([]api.ToolCall, 0, len(res.Content))
+	for _, block := range res.Content {
+		switch b := block.AsAny().(type) {
+		case anthropic.TextBlock:
+			content += b.Text
+		case anthropic.ToolUseBlock:
+			var args map[string]any
+			json.Unmarshal(b.Input, &args)
+			calls = append(calls, api.ToolCall{
+				Function: api.ToolCallFunction{Name: b.Name, Arguments: args},
+			})
+		}
+	}
+	return api.Message{Role: "assistant", Content: content, ToolCalls: calls}
+}
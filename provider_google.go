@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
+	"google.golang.org/genai"
+)
+
+// googleProvider speaks the Gemini API. Gemini groups tools under a single
+// `functionDeclarations` list and has no dedicated tool-result role, so
+// tool messages are folded back in as user content.
+type googleProvider struct {
+	client *genai.Client
+}
+
+func newGoogleProvider() (ChatCompletionProvider, error) {
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:  envOr("GOOGLE_API_KEY", ""),
+		Backend: genai.BackendGeminiAPI,
+	})
+	return &googleProvider{client: client}, err
+}
+
+func (p *googleProvider) Chat(ctx context.Context, req ChatRequest) (api.Message, error) {
+	system, messages := splitSystemPrompt(req.Messages)
+	res, err := p.client.Models.GenerateContent(ctx, req.Model, toGoogleContents(messages, req.Tools), &genai.GenerateContentConfig{
+		SystemInstruction: genai.NewContentFromText(system, genai.RoleUser),
+		Tools:             toGoogleTools(req.Tools),
+	})
+	if err != nil {
+		return api.Message{}, err
+	}
+	message := fromGoogleResponse(res)
+	// Gemini support doesn't stream incrementally yet; report the whole
+	// reply as a single chunk so callers can treat every backend uniformly.
+	if req.OnToken != nil && message.Content != "" {
+		req.OnToken(message.Content)
+	}
+	return message, nil
+}
+
+func toGoogleTools(tools []tool.Function) []*genai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	declarations := make([]*genai.FunctionDeclaration, 0, len(tools))
+	for _, t := range tools {
+		var schema *genai.Schema
+		b, _ := json.Marshal(t.Parameters)
+		json.Unmarshal(b, &schema)
+		declarations = append(declarations, &genai.FunctionDeclaration{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  schema,
+		})
+	}
+	return []*genai.Tool{{FunctionDeclarations: declarations}}
+}
+
+// toGoogleContents translates history into Gemini's Content shape. A
+// tool-result message is identified by Role matching one of tools' names
+// (the convention every Tool.Run follows, see tools.go), not a generic
+// "tool" literal. Tool calls carry no id anywhere else in the pipeline, so
+// an assistant message's function calls are assigned synthetic,
+// request-local ids that the immediately-following tool-result messages are
+// paired with in order, satisfying Gemini's function-response matching
+// within this request. Gemini documents batching every function response
+// for one turn into a single Content, so every contiguous run of
+// tool-result messages becomes one Content with one FunctionResponse part
+// per result, not one Content per result.
+func toGoogleContents(messages []api.Message, tools []tool.Function) []*genai.Content {
+	toolNames := toolNameSet(tools)
+	out := make([]*genai.Content, 0, len(messages))
+	var pendingIDs []string
+	var pendingResults []*genai.Part
+	flushResults := func() {
+		if len(pendingResults) > 0 {
+			out = append(out, &genai.Content{Role: genai.RoleUser, Parts: pendingResults})
+			pendingResults = nil
+		}
+	}
+	for _, m := range messages {
+		switch {
+		case m.Role == "assistant":
+			flushResults()
+			if len(m.ToolCalls) == 0 {
+				out = append(out, genai.NewContentFromText(m.Content, genai.RoleModel))
+				pendingIDs = nil
+				continue
+			}
+			parts := make([]*genai.Part, len(m.ToolCalls))
+			pendingIDs = make([]string, len(m.ToolCalls))
+			for i, c := range m.ToolCalls {
+				id := fmt.Sprintf("call_%d", i)
+				parts[i] = &genai.Part{FunctionCall: &genai.FunctionCall{
+					ID:   id,
+					Name: c.Function.Name,
+					Args: c.Function.Arguments,
+				}}
+				pendingIDs[i] = id
+			}
+			out = append(out, &genai.Content{Role: genai.RoleModel, Parts: parts})
+		case toolNames[m.Role]:
+			var id string
+			if len(pendingIDs) > 0 {
+				id, pendingIDs = pendingIDs[0], pendingIDs[1:]
+			}
+			pendingResults = append(pendingResults, &genai.Part{
+				FunctionResponse: &genai.FunctionResponse{
+					ID:       id,
+					Name:     m.Role,
+					Response: map[string]any{"result": m.Content},
+				},
+			})
+		default:
+			flushResults()
+			out = append(out, genai.NewContentFromText(m.Content, genai.RoleUser))
+			pendingIDs = nil
+		}
+	}
+	flushResults()
+	return out
+}
+
+func fromGoogleResponse(res *genai.GenerateContentResponse) api.Message {
+	var content string
+	calls := make([]api.ToolCall, 0)
+	for _, cand := range res.Candidates {
+		if cand.Content == nil {
+			continue
+		}
+		for _, part := range cand.Content.Parts {
+			if part.Text != "" {
+				content += part.Text
+			}
+			if part.FunctionCall != nil {
+				calls = append(calls, api.ToolCall{
+					Function: api.ToolCallFunction{
+						Name:      part.FunctionCall.Name,
+						Arguments: part.FunctionCall.Args,
+					},
+				})
+			}
+		}
+	}
+	return api.Message{Role: "assistant", Content: content, ToolCalls: calls}
+}
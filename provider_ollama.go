@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/grammar"
+)
+
+// ollamaProvider talks to a local or remote Ollama server. Its tool schema
+// is the same shape as Function, so no translation is required beyond the
+// existing Function.Tool() conversion.
+type ollamaProvider struct {
+	client *api.Client
+}
+
+func newOllamaProvider() (ChatCompletionProvider, error) {
+	client, err := api.ClientFromEnvironment()
+	return &ollamaProvider{client: client}, err
+}
+
+func (p *ollamaProvider) Chat(ctx context.Context, req ChatRequest) (api.Message, error) {
+	tools := make(api.Tools, 0, len(req.Tools))
+	for _, t := range req.Tools {
+		tools = append(tools, t.Tool())
+	}
+	think := req.Think
+	stream := req.OnToken != nil
+	var options map[string]any
+	if req.Grammar && len(req.Tools) > 0 {
+		options = map[string]any{"grammar": grammar.FromFunctions(req.Tools)}
+	}
+	var message api.Message
+	first := true
+	err := p.client.Chat(ctx, &api.ChatRequest{
+		Model:    req.Model,
+		Messages: req.Messages,
+		Tools:    tools,
+		Think:    &think,
+		Stream:   &stream,
+		Options:  options,
+	}, func(cr api.ChatResponse) error {
+		if !stream {
+			message = cr.Message
+			return nil
+		}
+		if cr.Message.Content != "" {
+			req.OnToken(cr.Message.Content)
+		}
+		if first {
+			message = cr.Message
+			first = false
+			return nil
+		}
+		message.Content += cr.Message.Content
+		if len(cr.Message.ToolCalls) != 0 {
+			message.ToolCalls = cr.Message.ToolCalls
+		}
+		return nil
+	})
+	return message, err
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ollama/ollama/api"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+// openAIProvider speaks the OpenAI Chat Completions API. Tool schemas are
+// translated from the canonical Function/Parameters into OpenAI's
+// function-calling shape, which is structurally identical to Ollama's.
+type openAIProvider struct {
+	client openai.Client
+}
+
+func newOpenAIProvider() (ChatCompletionProvider, error) {
+	return &openAIProvider{
+		client: openai.NewClient(option.WithAPIKey(envOr("OPENAI_API_KEY", ""))),
+	}, nil
+}
+
+func (p *openAIProvider) Chat(ctx context.Context, req ChatRequest) (api.Message, error) {
+	params := openai.ChatCompletionNewParams{
+		Model:    req.Model,
+		Messages: toOpenAIMessages(req.Messages, req.Tools),
+		Tools:    toOpenAITools(req.Tools),
+	}
+	res, err := p.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return api.Message{}, err
+	}
+	message := fromOpenAIMessage(res.Choices[0].Message)
+	// OpenAI support doesn't stream incrementally yet; report the whole
+	// reply as a single chunk so callers can treat every backend uniformly.
+	if req.OnToken != nil && message.Content != "" {
+		req.OnToken(message.Content)
+	}
+	return message, nil
+}
+
+func toOpenAITools(tools []tool.Function) []openai.ChatCompletionToolParam {
+	out := make([]openai.ChatCompletionToolParam, 0, len(tools))
+	for _, t := range tools {
+		var schema map[string]any
+		b, _ := json.Marshal(t.Parameters)
+		json.Unmarshal(b, &schema)
+		out = append(out, openai.ChatCompletionToolParam{
+			Function: openai.FunctionDefinitionParam{
+				Name:        t.Name,
+				Description: openai.String(t.Description),
+				Parameters:  schema,
+			},
+		})
+	}
+	return out
+}
+
+// toOpenAIMessages translates history into OpenAI's message shape. A
+// tool-result message is identified by Role matching one of tools' names
+// (the convention every Tool.Run follows, see tools.go), not a generic
+// "tool" literal. Tool calls carry no id anywhere else in the pipeline, so
+// an assistant message's calls are assigned synthetic, request-local ids
+// that the immediately-following tool-result messages are paired with in
+// order, satisfying OpenAI's tool_call_id matching within this request.
+func toOpenAIMessages(messages []api.Message, tools []tool.Function) []openai.ChatCompletionMessageParamUnion {
+	toolNames := toolNameSet(tools)
+	out := make([]openai.ChatCompletionMessageParamUnion, 0, len(messages))
+	var pendingIDs []string
+	for _, m := range messages {
+		switch {
+		case m.Role == "system":
+			out = append(out, openai.SystemMessage(m.Content))
+		case m.Role == "assistant":
+			if len(m.ToolCalls) == 0 {
+				out = append(out, openai.AssistantMessage(m.Content))
+				pendingIDs = nil
+				continue
+			}
+			calls := make([]openai.ChatCompletionMessageToolCallParam, len(m.ToolCalls))
+			pendingIDs = make([]string, len(m.ToolCalls))
+			for i, c := range m.ToolCalls {
+				id := fmt.Sprintf("call_%d", i)
+				args, _ := json.Marshal(c.Function.Arguments)
+				calls[i] = openai.ChatCompletionMessageToolCallParam{
+					ID: id,
+					Function: openai.ChatCompletionMessageToolCallFunctionParam{
+						Name:      c.Function.Name,
+						Arguments: string(args),
+					},
+				}
+				pendingIDs[i] = id
+			}
+			out = append(out, openai.ChatCompletionMessageParamUnion{
+				OfAssistant: &openai.ChatCompletionAssistantMessageParam{
+					Content:   openai.ChatCompletionAssistantMessageParamContentUnion{OfString: openai.String(m.Content)},
+					ToolCalls: calls,
+				},
+			})
+		case toolNames[m.Role]:
+			var id string
+			if len(pendingIDs) > 0 {
+				id, pendingIDs = pendingIDs[0], pendingIDs[1:]
+			}
+			out = append(out, openai.ToolMessage(m.Content, id))
+		default:
+			out = append(out, openai.UserMessage(m.Content))
+			pendingIDs = nil
+		}
+	}
+	return out
+}
+
+func fromOpenAIMessage(m openai.ChatCompletionMessage) api.Message {
+	calls := make([]api.ToolCall, 0, len(m.ToolCalls))
+	for _, c := range m.ToolCalls {
+		var args map[string]any
+		json.Unmarshal([]byte(c.Function.Arguments), &args)
+		calls = append(calls, api.ToolCall{
+			Function: api.ToolCallFunction{
+				Name:      c.Function.Name,
+				Arguments: args,
+			},
+		})
+	}
+	return api.Message{
+		Role:      "assistant",
+		Content:   m.Content,
+		ToolCalls: calls,
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	_ "modernc.org/sqlite"
+)
+
+// Store persists conversations and their message history to SQLite so
+// sessions survive past a single TUI run.
+type Store struct {
+	db *sql.DB
+}
+
+// Conversation is a row from the conversations table, as surfaced by
+// `pinky ls` and the huh conversations picker.
+type Conversation struct {
+	ID        int64
+	Title     string
+	Model     string
+	Backend   string
+	CreatedAt time.Time
+}
+
+// DefaultStorePath returns ~/.config/pinky/pinky.db, overridable with
+// PINKY_DB.
+func DefaultStorePath() string {
+	if path := os.Getenv("PINKY_DB"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "pinky.db"
+	}
+	return filepath.Join(dir, "pinky", "pinky.db")
+}
+
+// OpenStore opens (creating if needed) the SQLite database at path and
+// ensures its schema exists.
+func OpenStore(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	store := &Store{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`
+CREATE TABLE IF NOT EXISTS conversations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL DEFAULT '',
+	model TEXT NOT NULL DEFAULT '',
+	backend TEXT NOT NULL DEFAULT '',
+	head_message_id INTEGER REFERENCES messages(id),
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS messages (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	conversation_id INTEGER NOT NULL REFERENCES conversations(id),
+	parent_id INTEGER REFERENCES messages(id),
+	role TEXT NOT NULL,
+	content TEXT NOT NULL DEFAULT '',
+	tool_calls TEXT NOT NULL DEFAULT '[]',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`)
+	return err
+}
+
+func (s *Store) Close() error { return s.db.Close() }
+
+// NewConversation inserts an empty conversation row and returns its id.
+func (s *Store) NewConversation(model, backend string) (int64, error) {
+	res, err := s.db.Exec(`INSERT INTO conversations (model, backend) VALUES (?, ?)`, model, backend)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// SetTitle updates a conversation's auto-generated title.
+func (s *Store) SetTitle(conversationID int64, title string) error {
+	_, err := s.db.Exec(`UPDATE conversations SET title = ? WHERE id = ?`, title, conversationID)
+	return err
+}
+
+// MessageRow is a single node in a conversation's message tree.
+type MessageRow struct {
+	ID             int64
+	ConversationID int64
+	ParentID       int64 // 0 if this is the root message
+	Message        api.Message
+}
+
+// AppendMessage persists m as a child of parentID (0 for the conversation's
+// first message), sets it as the conversation's new head, and returns its
+// row id. Appending from any existing node forks a new sibling branch
+// there without disturbing the messages that were already in the tree.
+func (s *Store) AppendMessage(conversationID, parentID int64, m api.Message) (int64, error) {
+	calls, err := json.Marshal(m.ToolCalls)
+	if err != nil {
+		return 0, err
+	}
+	var parent any
+	if parentID != 0 {
+		parent = parentID
+	}
+	res, err := s.db.Exec(
+		`INSERT INTO messages (conversation_id, parent_id, role, content, tool_calls) VALUES (?, ?, ?, ?, ?)`,
+		conversationID, parent, m.Role, m.Content, string(calls),
+	)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return id, s.SetHead(conversationID, id)
+}
+
+// SetHead moves a conversation's head pointer, e.g. after forking a branch
+// or switching to a sibling.
+func (s *Store) SetHead(conversationID, messageID int64) error {
+	_, err := s.db.Exec(`UPDATE conversations SET head_message_id = ? WHERE id = ?`, messageID, conversationID)
+	return err
+}
+
+// Head returns a conversation's current head message id, or 0 if it has no
+// messages yet.
+func (s *Store) Head(conversationID int64) (int64, error) {
+	var head sql.NullInt64
+	err := s.db.QueryRow(`SELECT head_message_id FROM conversations WHERE id = ?`, conversationID).Scan(&head)
+	if err != nil {
+		return 0, err
+	}
+	return head.Int64, nil
+}
+
+// Message loads a single message row by id.
+func (s *Store) Message(id int64) (MessageRow, error) {
+	var row MessageRow
+	var parent sql.NullInt64
+	var role, content, calls string
+	err := s.db.QueryRow(
+		`SELECT id, conversation_id, parent_id, role, content, tool_calls FROM messages WHERE id = ?`, id,
+	).Scan(&row.ID, &row.ConversationID, &parent, &role, &content, &calls)
+	if err != nil {
+		return MessageRow{}, err
+	}
+	row.ParentID = parent.Int64
+	var toolCalls []api.ToolCall
+	if err := json.Unmarshal([]byte(calls), &toolCalls); err != nil {
+		return MessageRow{}, err
+	}
+	row.Message = api.Message{Role: role, Content: content, ToolCalls: toolCalls}
+	return row, nil
+}
+
+// Siblings returns every message sharing id's parent (including id
+// itself), ordered by creation, so a TUI can offer them as alternative
+// branches at that point in the conversation.
+func (s *Store) Siblings(id int64) ([]MessageRow, error) {
+	node, err := s.Message(id)
+	if err != nil {
+		return nil, err
+	}
+	var rows *sql.Rows
+	if node.ParentID == 0 {
+		rows, err = s.db.Query(
+			`SELECT id, conversation_id, parent_id, role, content, tool_calls FROM messages WHERE conversation_id = ? AND parent_id IS NULL ORDER BY id`,
+			node.ConversationID,
+		)
+	} else {
+		rows, err = s.db.Query(
+			`SELECT id, conversation_id, parent_id, role, content, tool_calls FROM messages WHERE parent_id = ? ORDER BY id`,
+			node.ParentID,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var siblings []MessageRow
+	for rows.Next() {
+		var row MessageRow
+		var parent sql.NullInt64
+		var role, content, calls string
+		if err := rows.Scan(&row.ID, &row.ConversationID, &parent, &role, &content, &calls); err != nil {
+			return nil, err
+		}
+		row.ParentID = parent.Int64
+		var toolCalls []api.ToolCall
+		if err := json.Unmarshal([]byte(calls), &toolCalls); err != nil {
+			return nil, err
+		}
+		row.Message = api.Message{Role: role, Content: content, ToolCalls: toolCalls}
+		siblings = append(siblings, row)
+	}
+	return siblings, rows.Err()
+}
+
+// Leaf walks down from id, at each step taking the most recently created
+// child, and returns the id it bottoms out at (id itself if it has no
+// children). Switching to a sibling branch should land on the deepest point
+// previously explored down that sibling, not just the sibling node itself,
+// so earlier exploration stays reachable.
+func (s *Store) Leaf(id int64) (int64, error) {
+	current := id
+	for {
+		var next int64
+		err := s.db.QueryRow(
+			`SELECT id FROM messages WHERE parent_id = ? ORDER BY id DESC LIMIT 1`, current,
+		).Scan(&next)
+		if err == sql.ErrNoRows {
+			return current, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		current = next
+	}
+}
+
+// Path materialises the message chain from the conversation's root to its
+// current head, root first.
+func (s *Store) Path(conversationID int64) ([]MessageRow, error) {
+	head, err := s.Head(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if head == 0 {
+		return nil, nil
+	}
+	var reversed []MessageRow
+	for id := head; id != 0; {
+		row, err := s.Message(id)
+		if err != nil {
+			return nil, err
+		}
+		reversed = append(reversed, row)
+		id = row.ParentID
+	}
+	path := make([]MessageRow, len(reversed))
+	for i, row := range reversed {
+		path[len(reversed)-1-i] = row
+	}
+	return path, nil
+}
+
+// History is a convenience wrapper over Path for callers that only need the
+// messages and the head id to resume appending (or forking) from.
+func (s *Store) History(conversationID int64) ([]api.Message, int64, error) {
+	path, err := s.Path(conversationID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(path) == 0 {
+		return nil, 0, nil
+	}
+	messages := make([]api.Message, len(path))
+	for i, row := range path {
+		messages[i] = row.Message
+	}
+	return messages, path[len(path)-1].ID, nil
+}
+
+// List returns every conversation, most recently created first.
+func (s *Store) List() ([]Conversation, error) {
+	rows, err := s.db.Query(`SELECT id, title, model, backend, created_at FROM conversations ORDER BY id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var conversations []Conversation
+	for rows.Next() {
+		var c Conversation
+		if err := rows.Scan(&c.ID, &c.Title, &c.Model, &c.Backend, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, c)
+	}
+	return conversations, rows.Err()
+}
+
+// Delete removes a conversation and its messages.
+func (s *Store) Delete(conversationID int64) error {
+	if _, err := s.db.Exec(`DELETE FROM messages WHERE conversation_id = ?`, conversationID); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`DELETE FROM conversations WHERE id = ?`, conversationID)
+	return err
+}
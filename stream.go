@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tokenMsg carries one streamed chunk of the assistant's reply to the
+// bubbletea update loop.
+type tokenMsg string
+
+// streamDoneMsg signals that the background loop call finished.
+type streamDoneMsg struct{ err error }
+
+// streamModel renders tokens live as they arrive from a running Brain.loop
+// call, replacing the static "thinking..." spinner with the reply itself.
+type streamModel struct {
+	tokens  <-chan string
+	done    <-chan error
+	cancel  context.CancelFunc
+	content string
+	err     error
+}
+
+func newStreamModel(tokens <-chan string, done <-chan error, cancel context.CancelFunc) streamModel {
+	return streamModel{tokens: tokens, done: done, cancel: cancel}
+}
+
+func (m streamModel) Init() tea.Cmd {
+	return m.wait()
+}
+
+// wait blocks on either the next token or the loop call's completion.
+func (m streamModel) wait() tea.Cmd {
+	tokens, done := m.tokens, m.done
+	return func() tea.Msg {
+		select {
+		case t, ok := <-tokens:
+			if !ok {
+				return streamDoneMsg{err: <-done}
+			}
+			return tokenMsg(t)
+		case err := <-done:
+			return streamDoneMsg{err: err}
+		}
+	}
+}
+
+func (m streamModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tokenMsg:
+		m.content += string(msg)
+		return m, m.wait()
+	case streamDoneMsg:
+		m.err = msg.err
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			// Cancel the in-flight provider call rather than quitting
+			// outright: the wait() already in flight keeps listening, and
+			// will deliver streamDoneMsg once fn notices ctx is done and
+			// returns, so we quit only after the background goroutine has
+			// actually stopped.
+			if m.cancel != nil {
+				m.cancel()
+			}
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+func (m streamModel) View() string {
+	return m.content
+}
+
+// runStreaming drives fn in the background, rendering every token it reports
+// through the callback as it arrives, and returns fn's error. Ctrl-C cancels
+// the ctx passed to fn instead of abandoning it, so fn can wind down and
+// (per Brain.chat) persist whatever partial reply had already streamed.
+func runStreaming(ctx context.Context, fn func(ctx context.Context, onToken func(string)) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tokens := make(chan string)
+	done := make(chan error, 1)
+	go func() {
+		err := fn(ctx, func(tok string) {
+			// Once cancelled there may be no reader left; don't block
+			// forever trying to deliver a token nobody will render.
+			select {
+			case tokens <- tok:
+			case <-ctx.Done():
+			}
+		})
+		close(tokens)
+		done <- err
+	}()
+	final, err := tea.NewProgram(newStreamModel(tokens, done, cancel)).Run()
+	if err != nil {
+		return err
+	}
+	return final.(streamModel).err
+}
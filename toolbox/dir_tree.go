@@ -0,0 +1,84 @@
+package toolbox
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+const maxTreeDepth = 5
+
+type dirTree struct{ m *Manager }
+
+func (t dirTree) Name() string { return "dir_tree" }
+
+func (t dirTree) Description() tool.Function {
+	return tool.Function{
+		Name:        t.Name(),
+		Description: "Return a JSON tree of files and directories under path, capped at 5 levels deep.",
+		Parameters: tool.Parameters{
+			Type:     "object",
+			Required: []string{"path"},
+			Properties: map[string]*tool.Property{
+				"path":  {Type: "string", Description: "directory path relative to the workspace root"},
+				"depth": {Type: "integer", Description: "how many levels to descend, capped at 5"},
+			},
+		},
+	}
+}
+
+type treeNode struct {
+	Name     string     `json:"name"`
+	Dir      bool       `json:"dir"`
+	Children []treeNode `json:"children,omitempty"`
+}
+
+func (t dirTree) Run(ctx context.Context, call api.ToolCallFunction) (api.Message, error) {
+	path, _ := call.Arguments["path"].(string)
+	depth := maxTreeDepth
+	if v, ok := call.Arguments["depth"].(float64); ok && int(v) < depth {
+		depth = int(v)
+	}
+	root, err := t.m.resolve(path)
+	if err != nil {
+		return api.Message{}, err
+	}
+	node, err := walkTree(root, filepath.Base(root), depth)
+	if err != nil {
+		return api.Message{}, err
+	}
+	b, err := json.MarshalIndent(node, "", "  ")
+	if err != nil {
+		return api.Message{}, err
+	}
+	return api.Message{Role: t.Name(), Content: string(b)}, nil
+}
+
+func walkTree(path, name string, depth int) (treeNode, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return treeNode{}, err
+	}
+	node := treeNode{Name: name, Dir: info.IsDir()}
+	if !info.IsDir() || depth <= 0 {
+		return node, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return treeNode{}, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, entry := range entries {
+		child, err := walkTree(filepath.Join(path, entry.Name()), entry.Name(), depth-1)
+		if err != nil {
+			return treeNode{}, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
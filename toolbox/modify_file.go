@@ -0,0 +1,131 @@
+package toolbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+// lineEdit replaces the inclusive 1-indexed [StartLine, EndLine] range with
+// Replacement.
+type lineEdit struct {
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	Replacement string `json:"replacement"`
+}
+
+type modifyFile struct{ m *Manager }
+
+func (t modifyFile) Name() string { return "modify_file" }
+
+func (t modifyFile) Description() tool.Function {
+	return tool.Function{
+		Name:        t.Name(),
+		Description: "Apply a list of {start_line, end_line, replacement} edits to a file in a single atomic pass, returning a preview diff.",
+		Parameters: tool.Parameters{
+			Type:     "object",
+			Required: []string{"path", "edits"},
+			Properties: map[string]*tool.Property{
+				"path": {Type: "string", Description: "file path relative to the workspace root"},
+				"edits": {
+					Type:        "array",
+					Description: "list of {start_line, end_line, replacement} edits, 1-indexed and inclusive",
+					Items: &tool.Property{
+						Type:     "object",
+						Required: []string{"start_line", "end_line", "replacement"},
+						Properties: map[string]*tool.Property{
+							"start_line":  {Type: "integer", Description: "first line of the range to replace, 1-indexed and inclusive"},
+							"end_line":    {Type: "integer", Description: "last line of the range to replace, 1-indexed and inclusive"},
+							"replacement": {Type: "string", Description: "text to substitute for the replaced range"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (t modifyFile) Run(ctx context.Context, call api.ToolCallFunction) (api.Message, error) {
+	path, _ := call.Arguments["path"].(string)
+	abs, err := t.m.resolve(path)
+	if err != nil {
+		return api.Message{}, err
+	}
+	raw, err := json.Marshal(call.Arguments["edits"])
+	if err != nil {
+		return api.Message{}, err
+	}
+	var edits []lineEdit
+	if err := json.Unmarshal(raw, &edits); err != nil {
+		return api.Message{}, fmt.Errorf("modify_file: invalid edits: %w", err)
+	}
+
+	original, err := os.ReadFile(abs)
+	if err != nil {
+		return api.Message{}, err
+	}
+	before := strings.Split(string(original), "\n")
+	after, err := applyEdits(before, edits)
+	if err != nil {
+		return api.Message{}, err
+	}
+	if err := os.WriteFile(abs, []byte(strings.Join(after, "\n")), 0o644); err != nil {
+		return api.Message{}, err
+	}
+	return api.Message{Role: t.Name(), Content: previewDiff(before, after)}, nil
+}
+
+// applyEdits applies edits from the bottom of the file up, so earlier
+// edits' line numbers stay valid as later ones shift the line count.
+func applyEdits(lines []string, edits []lineEdit) ([]string, error) {
+	if err := checkOverlaps(edits); err != nil {
+		return nil, err
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].StartLine > edits[j].StartLine })
+	result := append([]string(nil), lines...)
+	for _, e := range edits {
+		start, end := e.StartLine-1, e.EndLine
+		if start < 0 || end > len(result) || start > end {
+			return nil, fmt.Errorf("modify_file: edit [%d,%d] out of range for %d lines", e.StartLine, e.EndLine, len(result))
+		}
+		replacement := strings.Split(e.Replacement, "\n")
+		result = append(result[:start:start], append(replacement, result[end:]...)...)
+	}
+	return result, nil
+}
+
+// checkOverlaps rejects a batch whose original (pre-mutation) line ranges
+// overlap. Applying such a batch bottom-up would have one edit silently
+// drop or overwrite part of another instead of erroring, so this runs
+// against the ranges the caller supplied, before any edit is applied.
+func checkOverlaps(edits []lineEdit) error {
+	sorted := append([]lineEdit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartLine < sorted[j].StartLine })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].StartLine <= sorted[i-1].EndLine {
+			return fmt.Errorf("modify_file: edits [%d,%d] and [%d,%d] overlap",
+				sorted[i-1].StartLine, sorted[i-1].EndLine, sorted[i].StartLine, sorted[i].EndLine)
+		}
+	}
+	return nil
+}
+
+// previewDiff renders a minimal before/after preview; it's not a full LCS
+// diff, just enough context for the model to confirm what changed.
+func previewDiff(before, after []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- before (%d lines)\n+++ after (%d lines)\n", len(before), len(after))
+	for _, l := range before {
+		fmt.Fprintf(&b, "-%s\n", l)
+	}
+	for _, l := range after {
+		fmt.Fprintf(&b, "+%s\n", l)
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package toolbox
+
+import "testing"
+
+func TestApplyEditsRejectsOverlappingRanges(t *testing.T) {
+	lines := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+	edits := []lineEdit{
+		{StartLine: 7, EndLine: 10, Replacement: "a"},
+		{StartLine: 5, EndLine: 8, Replacement: "b"},
+	}
+	if _, err := applyEdits(lines, edits); err == nil {
+		t.Fatal("expected an error for overlapping edits, got nil")
+	}
+}
+
+func TestApplyEditsAcceptsDisjointRanges(t *testing.T) {
+	lines := []string{"1", "2", "3", "4", "5"}
+	edits := []lineEdit{
+		{StartLine: 4, EndLine: 5, Replacement: "x"},
+		{StartLine: 1, EndLine: 2, Replacement: "y"},
+	}
+	got, err := applyEdits(lines, edits)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"y", "3", "x"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package toolbox
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+type readFile struct{ m *Manager }
+
+func (t readFile) Name() string { return "read_file" }
+
+func (t readFile) Description() tool.Function {
+	return tool.Function{
+		Name:        t.Name(),
+		Description: "Read a file's contents, optionally restricted to a line range.",
+		Parameters: tool.Parameters{
+			Type:     "object",
+			Required: []string{"path"},
+			Properties: map[string]*tool.Property{
+				"path":       {Type: "string", Description: "file path relative to the workspace root"},
+				"start_line": {Type: "integer", Description: "first line to include, 1-indexed"},
+				"end_line":   {Type: "integer", Description: "last line to include, inclusive"},
+			},
+		},
+	}
+}
+
+func (t readFile) Run(ctx context.Context, call api.ToolCallFunction) (api.Message, error) {
+	path, _ := call.Arguments["path"].(string)
+	abs, err := t.m.resolve(path)
+	if err != nil {
+		return api.Message{}, err
+	}
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return api.Message{}, err
+	}
+	lines := strings.Split(string(content), "\n")
+	start, end := lineRange(call.Arguments, len(lines))
+	return api.Message{Role: t.Name(), Content: strings.Join(lines[start:end], "\n")}, nil
+}
+
+// lineRange clamps the optional start_line/end_line arguments to a valid,
+// 0-indexed [start, end) slice bound for a file of the given length. Both
+// bounds are clamped to [0, total] before use, since a model-supplied
+// start_line/end_line (e.g. negative) would otherwise produce a negative
+// slice index and panic.
+func lineRange(args map[string]any, total int) (int, int) {
+	start, end := 0, total
+	if v, ok := args["start_line"].(float64); ok {
+		start = clampLine(int(v)-1, total)
+	}
+	if v, ok := args["end_line"].(float64); ok {
+		end = clampLine(int(v), total)
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+// clampLine bounds n to [0, total].
+func clampLine(n, total int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > total {
+		return total
+	}
+	return n
+}
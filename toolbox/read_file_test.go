@@ -0,0 +1,17 @@
+package toolbox
+
+import "testing"
+
+func TestLineRangeClampsNegativeEndLine(t *testing.T) {
+	start, end := lineRange(map[string]any{"end_line": float64(-5)}, 10)
+	if start != 0 || end != 0 {
+		t.Errorf("got [%d,%d], want [0,0]", start, end)
+	}
+}
+
+func TestLineRangeClampsOutOfBoundsLines(t *testing.T) {
+	start, end := lineRange(map[string]any{"start_line": float64(-3), "end_line": float64(100)}, 10)
+	if start != 0 || end != 10 {
+		t.Errorf("got [%d,%d], want [0,10]", start, end)
+	}
+}
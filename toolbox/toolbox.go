@@ -0,0 +1,53 @@
+// Package toolbox implements the built-in filesystem tools (dir_tree,
+// read_file, write_file, modify_file) that give pinky a "code assistant"
+// mode alongside the web_search tool.
+package toolbox
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+// Manager owns the workspace root every toolbox tool is confined to, and a
+// read-only switch that drops the mutating tools from Tools().
+type Manager struct {
+	root     string
+	readOnly bool
+}
+
+// NewManager resolves root to an absolute path and returns a Manager
+// confined to it. When readOnly is true, Tools omits write_file and
+// modify_file.
+func NewManager(root string, readOnly bool) (*Manager, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{root: abs, readOnly: readOnly}, nil
+}
+
+// Tools returns the filesystem toolbox, ready to register on a toolManager.
+func (m *Manager) Tools() []tool.Tool {
+	tools := []tool.Tool{dirTree{m}, readFile{m}}
+	if !m.readOnly {
+		tools = append(tools, writeFile{m}, modifyFile{m})
+	}
+	return tools
+}
+
+// resolve validates that ref, joined to the workspace root, does not escape
+// it (e.g. via ".."), and returns its absolute path.
+func (m *Manager) resolve(ref string) (string, error) {
+	abs, err := filepath.Abs(filepath.Join(m.root, ref))
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(m.root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes workspace root", ref)
+	}
+	return abs, nil
+}
@@ -0,0 +1,23 @@
+package toolbox
+
+import "testing"
+
+func TestResolveRejectsWorkspaceEscape(t *testing.T) {
+	m, err := NewManager(t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if _, err := m.resolve("../outside"); err == nil {
+		t.Fatal("expected an error for a path escaping the workspace root, got nil")
+	}
+}
+
+func TestResolveAllowsPathWithinRoot(t *testing.T) {
+	m, err := NewManager(t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if _, err := m.resolve("sub/file.txt"); err != nil {
+		t.Fatalf("unexpected error for path within workspace root: %v", err)
+	}
+}
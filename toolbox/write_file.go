@@ -0,0 +1,46 @@
+package toolbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
+)
+
+type writeFile struct{ m *Manager }
+
+func (t writeFile) Name() string { return "write_file" }
+
+func (t writeFile) Description() tool.Function {
+	return tool.Function{
+		Name:        t.Name(),
+		Description: "Create or overwrite a file with the given content.",
+		Parameters: tool.Parameters{
+			Type:     "object",
+			Required: []string{"path", "content"},
+			Properties: map[string]*tool.Property{
+				"path":    {Type: "string", Description: "file path relative to the workspace root"},
+				"content": {Type: "string", Description: "full file content to write"},
+			},
+		},
+	}
+}
+
+func (t writeFile) Run(ctx context.Context, call api.ToolCallFunction) (api.Message, error) {
+	path, _ := call.Arguments["path"].(string)
+	content, _ := call.Arguments["content"].(string)
+	abs, err := t.m.resolve(path)
+	if err != nil {
+		return api.Message{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+		return api.Message{}, err
+	}
+	if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+		return api.Message{}, err
+	}
+	return api.Message{Role: t.Name(), Content: fmt.Sprintf("wrote %d bytes to %s", len(content), path)}, nil
+}
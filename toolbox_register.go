@@ -0,0 +1,20 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/twistedogic/pinky/toolbox"
+)
+
+// PINKY_WORKSPACE and PINKY_READONLY configure the built-in filesystem
+// toolbox registered on defaultTools.
+func init() {
+	manager, err := toolbox.NewManager(envOr("PINKY_WORKSPACE", "."), os.Getenv("PINKY_READONLY") == "true")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := defaultTools.AddTool(manager.Tools()...); err != nil {
+		log.Fatal(err)
+	}
+}
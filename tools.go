@@ -2,147 +2,170 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"sync"
 
 	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/ollama/ollama/api"
+	"github.com/twistedogic/pinky/pkg/tool"
 	"github.com/twistedogic/serp"
 )
 
-type Tool interface {
-	Description() api.Tool
-	Run(context.Context, api.ToolCallFunction) (api.Message, error)
-}
-
-type Function struct {
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Parameters  Parameters `json:"parameters"`
-}
-
-func (f Function) ToolFunction() api.ToolFunction {
-	var function api.ToolFunction
-	b, _ := json.Marshal(&f)
-	json.Unmarshal(b, &function)
-	return function
-}
-
-func (f Function) Tool() api.Tool {
-	return api.Tool{
-		Type:     "function",
-		Function: f.ToolFunction(),
-	}
-}
-
-type Parameters struct {
-	Type       string               `json:"type"`
-	Required   []string             `json:"required,omitempty"`
-	Properties map[string]*Property `json:"properties"`
-}
-
-type Property struct {
-	Type        string   `json:"type"`
-	Description string   `json:"description"`
-	Enum        []string `json:"enum,omitempty"`
-}
-
-func fromMCPTool(tool *mcp.Tool) (api.Tool, error) {
-	openTool := tool.InputSchema
-	parameters := Parameters{Required: openTool.Required, Properties: make(map[string]*Property)}
+func fromMCPTool(mcpTool *mcp.Tool) (tool.Function, error) {
+	openTool := mcpTool.InputSchema
+	parameters := tool.Parameters{Required: openTool.Required, Properties: make(map[string]*tool.Property)}
 	for name, param := range openTool.Properties {
 		enums := make([]string, 0, len(param.Enum))
 		for _, e := range param.Enum {
 			str, ok := e.(string)
 			if !ok {
-				return api.Tool{}, fmt.Errorf("toOllamaTools: enum must be string, but got %v", e)
+				return tool.Function{}, fmt.Errorf("fromMCPTool: enum must be string, but got %v", e)
 			}
 			enums = append(enums, str)
 		}
-		parameters.Properties[name] = &Property{
+		parameters.Properties[name] = &tool.Property{
 			Type:        param.Type,
 			Description: param.Description,
 			Enum:        enums,
 		}
 	}
-	b, err := json.Marshal(Function{
-		Name:        tool.Name,
-		Description: tool.Description,
+	return tool.Function{
+		Name:        mcpTool.Name,
+		Description: mcpTool.Description,
 		Parameters:  parameters,
-	})
-	if err != nil {
-		return api.Tool{}, err
-	}
-	var function api.ToolFunction
-	if err := json.Unmarshal(b, &function); err != nil {
-		return api.Tool{}, err
-	}
-	return api.Tool{
-		Type:     "function",
-		Function: function,
 	}, nil
 }
 
-func FromMCPClient(ctx context.Context, client *mcp.ClientSession) ([]api.Tool, error) {
-	tools := make([]api.Tool, 0)
+// FromMCPClient enumerates the tools exposed by an MCP session and returns
+// them as canonical Functions, leaving per-backend encoding to the caller.
+func FromMCPClient(ctx context.Context, client *mcp.ClientSession) ([]tool.Function, error) {
+	functions := make([]tool.Function, 0)
 	var cursor string
 	for {
 		res, err := client.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
 		if err != nil {
 			return nil, err
 		}
-		for _, tool := range res.Tools {
+		for _, mcpTool := range res.Tools {
+			function, err := fromMCPTool(mcpTool)
 			if err != nil {
 				return nil, err
 			}
-			apiTool, err := fromMCPTool(tool)
-			if err != nil {
-				return nil, err
-			}
-			tools = append(tools, apiTool)
+			functions = append(functions, function)
 		}
 		if res.NextCursor == "" {
 			break
 		}
 		cursor = res.NextCursor
 	}
-	return tools, nil
+	return functions, nil
 }
 
+// ToolPolicy controls whether a tool call runs without prompting.
+type ToolPolicy string
+
+const (
+	PolicyAsk    ToolPolicy = "ask"
+	PolicyAlways ToolPolicy = "always"
+	PolicyNever  ToolPolicy = "never"
+)
+
 type toolManager struct {
-	tools map[string]Tool
+	mu         sync.Mutex
+	tools      map[string]tool.Tool
+	policies   map[string]ToolPolicy
+	mcpServers map[string]*mcpServer
 }
 
 func NewToolManager() *toolManager {
-	return &toolManager{tools: make(map[string]Tool)}
+	return &toolManager{
+		tools:      make(map[string]tool.Tool),
+		policies:   make(map[string]ToolPolicy),
+		mcpServers: make(map[string]*mcpServer),
+	}
 }
 
-func (m *toolManager) AddTool(tools ...Tool) error {
-	for _, tool := range tools {
-		name := tool.Description().Function.Name
+// Policy returns the confirmation policy for name, defaulting to PolicyAsk.
+func (m *toolManager) Policy(name string) ToolPolicy {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p, ok := m.policies[name]; ok {
+		return p
+	}
+	return PolicyAsk
+}
+
+// SetPolicy records the confirmation policy to apply to future calls of name.
+func (m *toolManager) SetPolicy(name string, policy ToolPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies[name] = policy
+}
+
+func (m *toolManager) AddTool(tools ...tool.Tool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range tools {
+		name := t.Name()
 		if _, exist := m.tools[name]; exist {
 			return fmt.Errorf("tool with name %q already exists", name)
 		}
-		m.tools[name] = tool
+		m.tools[name] = t
 	}
 	return nil
 }
 
-func (m *toolManager) List() []api.Tool {
-	tools := make([]api.Tool, 0, len(m.tools))
-	for _, tool := range m.tools {
-		tools = append(tools, tool.Description())
+func (m *toolManager) List() []tool.Function {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	functions := make([]tool.Function, 0, len(m.tools))
+	for _, t := range m.tools {
+		functions = append(functions, t.Description())
 	}
-	return tools
+	return functions
 }
 
 func (m *toolManager) Call(ctx context.Context, c api.ToolCall) (api.Message, error) {
-	tool, exist := m.tools[c.Function.Name]
+	m.mu.Lock()
+	t, exist := m.tools[c.Function.Name]
+	m.mu.Unlock()
 	if !exist {
 		return api.Message{}, fmt.Errorf("called non-exist tool %q", c.Function.Name)
 	}
-	return tool.Run(ctx, c.Function)
+	return t.Run(ctx, c.Function)
+}
+
+// Functions returns the Function descriptions of every registered tool
+// whose name is in allowed, or of every registered tool when allowed is
+// nil. Brain uses this (instead of keeping its own map[string]tool.Tool
+// snapshot) so a
+// background tools/list_changed reload can safely mutate m.tools
+// concurrently with Brain building a request.
+func (m *toolManager) Functions(allowed map[string]bool) []tool.Function {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	functions := make([]tool.Function, 0, len(m.tools))
+	for name, t := range m.tools {
+		if allowed != nil && !allowed[name] {
+			continue
+		}
+		functions = append(functions, t.Description())
+	}
+	return functions
+}
+
+// Lookup returns the named tool, if it's both registered and present in
+// allowed (nil allowed permits any registered tool). See Functions for why
+// Brain goes through this rather than indexing its own copy of m.tools.
+func (m *toolManager) Lookup(name string, allowed map[string]bool) (tool.Tool, bool) {
+	if allowed != nil && !allowed[name] {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, exist := m.tools[name]
+	return t, exist
 }
 
 var defaultTools = NewToolManager()
@@ -151,25 +174,27 @@ type serper struct {
 	client serp.Serper
 }
 
-func NewSerper() Tool {
+func NewSerper() tool.Tool {
 	return serper{client: serp.New()}
 }
 
-func (s serper) Description() api.Tool {
-	return Function{
-		Name:        "web_search",
+func (s serper) Name() string { return "web_search" }
+
+func (s serper) Description() tool.Function {
+	return tool.Function{
+		Name:        s.Name(),
 		Description: "Perform web search for provided search_term and return response as markdown.",
-		Parameters: Parameters{
+		Parameters: tool.Parameters{
 			Type:     "object",
 			Required: []string{"search_term"},
-			Properties: map[string]*Property{
-				"search_term": &Property{
+			Properties: map[string]*tool.Property{
+				"search_term": &tool.Property{
 					Type:        "string",
 					Description: "term to search for web results",
 				},
 			},
 		},
-	}.Tool()
+	}
 }
 
 func (s serper) Run(ctx context.Context, call api.ToolCallFunction) (api.Message, error) {